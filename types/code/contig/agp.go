@@ -0,0 +1,390 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
+	"github.com/biogo/biogo/io/seqio/fasta"
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/biogo/seq/linear"
+	"github.com/biogo/store/step"
+)
+
+// AGPVersion is the version of the AGP specification implemented by AGPReader and AGPWriter.
+const AGPVersion = "2.1"
+
+// A Component is a feat.Feature describing the region of an AGP component sequence
+// that a Contig member was taken from. From and To are zero-based half-open
+// coordinates on the component. Members created by an AGPReader have their
+// Location set to a Component.
+type Component struct {
+	ID       string
+	Type     byte // AGP component type, for example 'W' for WGS contig.
+	From, To int
+}
+
+func (c Component) Start() int             { return c.From }
+func (c Component) End() int               { return c.To }
+func (c Component) Name() string           { return c.ID }
+func (c Component) Description() string    { return "AGP component" }
+func (c Component) Len() int               { return c.To - c.From }
+func (c Component) Location() feat.Feature { return nil }
+
+// ReadComponents reads FASTA formatted sequences from r and returns them keyed by
+// ID for use as the component source of an AGPReader.
+func ReadComponents(r io.Reader, alpha alphabet.Alphabet) (map[string]seq.Sequence, error) {
+	comps := make(map[string]seq.Sequence)
+	fr := fasta.NewReader(r, linear.NewSeq("", nil, alpha))
+	for {
+		s, err := fr.Read()
+		if err != nil {
+			if err != io.EOF {
+				return nil, err
+			}
+			break
+		}
+		comps[s.Name()] = s
+	}
+	return comps, nil
+}
+
+// agpLine is a parsed AGP line. Coordinates are zero-based half-open.
+type agpLine struct {
+	object     string
+	start, end int
+	part       int
+	typ        byte
+
+	// Component fields.
+	id     string
+	cStart int
+	cEnd   int
+	orient byte
+
+	// Gap fields.
+	gapLen   int
+	gapType  GapType
+	linkage  bool
+	evidence []Evidence
+}
+
+func (l agpLine) isGap() bool { return l.typ == 'N' || l.typ == 'U' }
+
+func parseAGPLine(text string, n int) (agpLine, error) {
+	f := strings.Split(text, "\t")
+	if len(f) < 9 {
+		return agpLine{}, fmt.Errorf("contig: agp line %d: too few fields", n)
+	}
+	var (
+		l   = agpLine{object: f[0]}
+		err error
+	)
+	l.start, err = strconv.Atoi(f[1])
+	if err != nil {
+		return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+	}
+	l.end, err = strconv.Atoi(f[2])
+	if err != nil {
+		return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+	}
+	if l.start < 1 || l.end < l.start {
+		return l, fmt.Errorf("contig: agp line %d: invalid object range", n)
+	}
+	l.start = feat.OneToZero(l.start)
+	l.part, err = strconv.Atoi(f[3])
+	if err != nil {
+		return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+	}
+	if len(f[4]) != 1 {
+		return l, fmt.Errorf("contig: agp line %d: invalid component type %q", n, f[4])
+	}
+	l.typ = f[4][0]
+
+	if l.isGap() {
+		l.gapLen, err = strconv.Atoi(f[5])
+		if err != nil {
+			return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+		}
+		if l.gapLen != l.end-l.start {
+			return l, fmt.Errorf("contig: agp line %d: gap length does not match object range", n)
+		}
+		l.gapType, err = parseGapType(f[6])
+		if err != nil {
+			return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+		}
+		switch f[7] {
+		case "yes":
+			l.linkage = true
+		case "no":
+		default:
+			return l, fmt.Errorf("contig: agp line %d: invalid linkage %q", n, f[7])
+		}
+		l.evidence, err = parseEvidence(f[8])
+		if err != nil {
+			return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+		}
+		return l, nil
+	}
+
+	l.id = f[5]
+	l.cStart, err = strconv.Atoi(f[6])
+	if err != nil {
+		return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+	}
+	l.cEnd, err = strconv.Atoi(f[7])
+	if err != nil {
+		return l, fmt.Errorf("contig: agp line %d: %v", n, err)
+	}
+	if l.cStart < 1 || l.cEnd < l.cStart {
+		return l, fmt.Errorf("contig: agp line %d: invalid component range", n)
+	}
+	l.cStart = feat.OneToZero(l.cStart)
+	if l.cEnd-l.cStart != l.end-l.start {
+		return l, fmt.Errorf("contig: agp line %d: component length does not match object range", n)
+	}
+	switch f[8] {
+	case "+", "-", "?", "0", "na":
+		l.orient = f[8][0]
+	default:
+		return l, fmt.Errorf("contig: agp line %d: invalid orientation %q", n, f[8])
+	}
+	return l, nil
+}
+
+// AGPReader reads AGP formatted scaffold descriptions, returning a Contig for
+// each object described.
+type AGPReader struct {
+	sc    *bufio.Scanner
+	comps map[string]seq.Sequence
+	alpha alphabet.Alphabet
+	line  int
+	next  *agpLine
+}
+
+// NewAGPReader returns a new AGPReader reading from r. Component sequences named
+// in component lines are obtained from comps and must use the alphabet alpha.
+func NewAGPReader(r io.Reader, comps map[string]seq.Sequence, alpha alphabet.Alphabet) *AGPReader {
+	return &AGPReader{
+		sc:    bufio.NewScanner(r),
+		comps: comps,
+		alpha: alpha,
+	}
+}
+
+// Read returns the next object described by the AGP stream as a Contig. Each
+// component line is added to the Contig as a member holding the referenced region
+// of the component sequence, reverse complemented if the component orientation
+// is '-', and with its Location set to a Component. Each gap line is added to the
+// Contig as a Gap. Lines of an object must be
+// contiguous in the stream. At the end of the stream Read returns io.EOF.
+func (r *AGPReader) Read() (*Contig, error) {
+	var lines []agpLine
+	if r.next != nil {
+		lines = append(lines, *r.next)
+		r.next = nil
+	}
+	for r.sc.Scan() {
+		r.line++
+		text := r.sc.Text()
+		if len(text) == 0 || text[0] == '#' {
+			continue
+		}
+		l, err := parseAGPLine(text, r.line)
+		if err != nil {
+			return nil, err
+		}
+		if len(lines) != 0 && l.object != lines[0].object {
+			r.next = &l
+			break
+		}
+		lines = append(lines, l)
+	}
+	if err := r.sc.Err(); err != nil {
+		return nil, err
+	}
+	if len(lines) == 0 {
+		return nil, io.EOF
+	}
+	return r.build(lines)
+}
+
+func (r *AGPReader) build(lines []agpLine) (*Contig, error) {
+	var length int
+	for _, l := range lines {
+		if l.end > length {
+			length = l.end
+		}
+	}
+	c, err := New(lines[0].object, length, r.alpha)
+	if err != nil {
+		return nil, err
+	}
+	for _, l := range lines {
+		if l.isGap() {
+			err = c.InsertGap(Gap{
+				Start:    l.start,
+				End:      l.end,
+				Type:     l.gapType,
+				Unknown:  l.typ == 'U',
+				Linkage:  l.linkage,
+				Evidence: l.evidence,
+			})
+			if err != nil {
+				return nil, err
+			}
+			continue
+		}
+		s, ok := r.comps[l.id]
+		if !ok {
+			return nil, fmt.Errorf("contig: agp: no sequence for component %q", l.id)
+		}
+		if l.cStart < s.Start() || l.cEnd > s.End() {
+			return nil, fmt.Errorf("contig: agp: component range out of range for %q", l.id)
+		}
+		b := make([]alphabet.Letter, 0, l.cEnd-l.cStart)
+		for i := l.cStart; i < l.cEnd; i++ {
+			b = append(b, s.At(i).L)
+		}
+		m := linear.NewSeq(l.id, b, r.alpha)
+		m.Loc = Component{ID: l.id, Type: l.typ, From: l.cStart, To: l.cEnd}
+		if l.orient == '-' {
+			m.RevComp()
+		}
+		m.SetOffset(l.start)
+		err = c.Insert(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// AGPWriter writes Contig layouts in AGP format.
+type AGPWriter struct {
+	w      io.Writer
+	header bool
+}
+
+// NewAGPWriter returns a new AGPWriter that writes to w.
+func NewAGPWriter(w io.Writer) *AGPWriter {
+	return &AGPWriter{w: w}
+}
+
+// Write writes the layout of c as an AGP object named with the ID of c. Each run
+// of positions provided by a single member is written as a component line, each Gap
+// is written as a gap line and each run of ground state positions is written as a
+// scaffold gap with unspecified linkage evidence. Where members overlap, the
+// member providing the letters under the Contig's Policy is written. Object
+// coordinates are relative to the start of c, so an AGP object always begins at
+// position 1. Component coordinates are taken from the member's Location if it
+// is a Component, and otherwise refer to the member as inserted. The orientation
+// of a component combines the orientation of the member sequence with that of
+// the member relative to the Contig.
+func (w *AGPWriter) Write(c *Contig) error {
+	if !w.header {
+		_, err := fmt.Fprintf(w.w, "##agp-version\t%s\n", AGPVersion)
+		if err != nil {
+			return err
+		}
+		w.header = true
+	}
+	var (
+		part   int
+		offset = c.Start()
+		err    error
+	)
+	c.walkTiles(func(start, end int, m *placement) {
+		if err != nil {
+			return
+		}
+		part++
+		oStart, oEnd := start-offset, end-offset
+		if m == nil {
+			g, ok := c.GapAt(start)
+			if !ok {
+				g = Gap{Type: ScaffoldGap, Linkage: true, Evidence: []Evidence{Unspecified}}
+			}
+			var (
+				typ     = 'N'
+				linkage = "no"
+			)
+			if g.Unknown {
+				typ = 'U'
+			}
+			if g.Linkage {
+				linkage = "yes"
+			}
+			_, err = fmt.Fprintf(w.w, "%s\t%d\t%d\t%d\t%c\t%d\t%s\t%s\t%s\n",
+				c.ID, feat.ZeroToOne(oStart), oEnd, part, typ, end-start, g.Type, linkage, formatEvidence(g.Evidence))
+			return
+		}
+		comp := Component{ID: m.s.Name(), Type: 'W', From: 0, To: m.s.Len()}
+		if cm, ok := m.s.Location().(Component); ok {
+			comp = cm
+		}
+		var (
+			seg    = c.segment(m, start, end)
+			orient = byte('+')
+			cStart = comp.From + seg.MemberStart
+			cEnd   = comp.From + seg.MemberEnd
+			rev    = seg.Strand == seq.Minus
+		)
+		if o, ok := m.s.(feat.Orienter); ok {
+			switch o.Orientation() {
+			case feat.Reverse:
+				rev = !rev
+				cStart, cEnd = comp.To-seg.MemberEnd, comp.To-seg.MemberStart
+			case feat.NotOriented:
+				orient = '?'
+			}
+		}
+		if rev && orient != '?' {
+			orient = '-'
+		}
+		_, err = fmt.Fprintf(w.w, "%s\t%d\t%d\t%d\t%c\t%s\t%d\t%d\t%c\n",
+			c.ID, feat.ZeroToOne(oStart), oEnd, part, comp.Type, comp.ID, feat.ZeroToOne(cStart), cEnd, orient)
+	})
+	return err
+}
+
+// walkTiles calls fn for each maximal run of positions in c that is provided by a
+// single member, passing the member, or for each gap or run of ground state
+// positions, passing a nil member. Where members overlap, the member providing
+// the letters under the Contig's Policy is used, as for At. Runs are passed in
+// the order of the Contig's current orientation.
+func (c *Contig) walkTiles(fn func(start, end int, m *placement)) {
+	var (
+		tStart, tEnd int
+		top          *placement
+		open         bool
+	)
+	tile := func(start, end int, p *placement) {
+		if open && p == top && p != nil {
+			tEnd = end
+			return
+		}
+		if open {
+			fn(tStart, tEnd, top)
+		}
+		tStart, tEnd, top, open = start, end, p, true
+	}
+	c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+		if ss, ok := e.(seqStep); ok {
+			c.choose(start, end, ss, tile)
+			return
+		}
+		tile(start, end, nil)
+	})
+	if open {
+		fn(tStart, tEnd, top)
+	}
+}
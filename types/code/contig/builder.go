@@ -0,0 +1,138 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"sync"
+
+	"github.com/biogo/biogo/feat"
+	"github.com/biogo/biogo/seq"
+)
+
+// errSnapshot is returned by methods that would modify a snapshot.
+var errSnapshot = errors.New("contig: snapshot is read-only")
+
+// checkWritable panics if the Contig is a snapshot.
+func (c *Contig) checkWritable() {
+	if c.frozen {
+		panic(errSnapshot)
+	}
+}
+
+// IsSnapshot returns whether the Contig is a read-only snapshot returned by a
+// Builder. Methods that would modify a snapshot return an error, or panic if
+// they do not return an error. Clone returns a modifiable copy of a snapshot.
+func (c *Contig) IsSnapshot() bool { return c.frozen }
+
+// A Builder holds a Contig that may be modified by concurrent goroutines. Each
+// modification is made while holding a lock on the Contig, so modifications are
+// applied in some serial order. Read-only snapshots of the Contig are returned
+// by Snapshot and may be shared and read by any number of goroutines without
+// locking.
+type Builder struct {
+	mu sync.Mutex
+	c  *Contig
+
+	// snap is the snapshot of c taken since
+	// its last modification, if any.
+	snap *Contig
+
+	// normalize specifies that snapshots
+	// are normalized to start at zero.
+	normalize bool
+}
+
+// NewBuilder returns a new Builder holding c. The Builder takes ownership of c,
+// which must not be used other than through the Builder. If c is a snapshot, the
+// Builder holds a modifiable copy of c.
+func NewBuilder(c *Contig) *Builder {
+	if c.frozen {
+		c = c.Clone().(*Contig)
+	}
+	return &Builder{c: c}
+}
+
+// Insert inserts s into the held Contig as described by Contig.Insert. The
+// sequence must not be modified while the Builder is in use.
+func (b *Builder) Insert(s seq.Sequence) error {
+	return b.Do(func(c *Contig) error { return c.Insert(s) })
+}
+
+// InsertGap marks a gap in the held Contig as described by Contig.InsertGap.
+func (b *Builder) InsertGap(g Gap) error {
+	return b.Do(func(c *Contig) error { return c.InsertGap(g) })
+}
+
+// Merge places src into the held Contig as described by Contig.Merge. Member
+// sequences of src are shared with the held Contig, so src must not be modified
+// while the Builder is in use. The member sequences of a snapshot are cloned,
+// so snapshots may be merged safely.
+func (b *Builder) Merge(src *Contig, at int, strand seq.Strand) error {
+	return b.Do(func(c *Contig) error { return c.Merge(src, at, strand) })
+}
+
+// Mask masks the interval [start, end) of the held Contig.
+func (b *Builder) Mask(start, end int) error {
+	return b.Do(func(c *Contig) error { return c.Mask(start, end) })
+}
+
+// Annotate attaches f to the held Contig as described by Contig.Annotate.
+func (b *Builder) Annotate(f feat.Feature) error {
+	return b.Do(func(c *Contig) error { return c.Annotate(f) })
+}
+
+// Remove removes the member with the given ID from the held Contig and returns it.
+func (b *Builder) Remove(id string) (seq.Sequence, error) {
+	var s seq.Sequence
+	err := b.Do(func(c *Contig) error {
+		var err error
+		s, err = c.Remove(id)
+		return err
+	})
+	return s, err
+}
+
+// Do calls fn with the held Contig while holding the Builder's lock, allowing
+// any sequence of operations to be made atomically. The Contig must not be
+// retained by fn. Do returns the error returned by fn.
+func (b *Builder) Do(fn func(c *Contig) error) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.snap = nil
+	return fn(b.c)
+}
+
+// NormalizeSnapshots sets whether snapshots returned by Snapshot are normalized
+// to start at position zero, as described by Contig.Normalize. The held Contig
+// is not normalized.
+func (b *Builder) NormalizeSnapshots(n bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if n != b.normalize {
+		b.snap = nil
+	}
+	b.normalize = n
+}
+
+// Snapshot returns a read-only copy of the held Contig in its current state.
+// Member sequences are cloned, so later modifications made through the Builder
+// are not reflected in the snapshot. Successive calls without an intervening
+// modification return the same snapshot.
+func (b *Builder) Snapshot() *Contig {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.snap == nil {
+		snap := b.c.Clone().(*Contig)
+		if b.normalize {
+			// Normalizing only re-inserts the existing
+			// members of the clone, so cannot fail.
+			snap.Normalize()
+		}
+		snap.frozen = true
+		b.snap = snap
+	}
+	return b.snap
+}
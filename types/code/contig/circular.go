@@ -0,0 +1,112 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
+)
+
+// NewCircular returns a new circular super contig sequence spanning the positions
+// [0, l) and using the provided alphabet's ambiguous letter as the step ground
+// state. Members inserted into a circular Contig may extend beyond its end, in
+// which case they continue from its start. A circular Contig is never relaxed.
+func NewCircular(id string, l int, a alphabet.Alphabet) (*Contig, error) {
+	c, err := New(id, l, a)
+	if err != nil {
+		return nil, err
+	}
+	c.Conform = feat.Circular
+	return c, nil
+}
+
+// IsCircular returns whether the Contig has a circular conformation.
+func (c *Contig) IsCircular() bool { return c.Conform == feat.Circular }
+
+// SetConformation sets the conformation of the Contig. The Contig may only be
+// changed between linear and circular conformations while it has no members.
+func (c *Contig) SetConformation(f feat.Conformation) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if len(c.members) != 0 && (f == feat.Circular) != c.IsCircular() {
+		return errors.New("contig: cannot change conformation of populated contig")
+	}
+	c.Conform = f
+	if c.IsCircular() && c.vector != nil {
+		c.vector.Relaxed = false
+	}
+	return nil
+}
+
+// Rotate moves the origin of a circular Contig to position o, so that the letter
+// at o becomes the letter at the Contig's start position. Members, gaps and
+// features are moved with their letters; gaps and masks spanning the new origin
+// are split.
+// Rotate returns an error if the Contig is not circular.
+func (c *Contig) Rotate(o int) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if !c.IsCircular() {
+		return errors.New("contig: cannot rotate linear contig")
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	err := c.Materialize()
+	if err != nil {
+		return err
+	}
+	start, n := c.Start(), c.Len()
+	d := mod(o-start, n)
+	if d == 0 {
+		return nil
+	}
+	rot := func(i int) int { return start + mod(i-start-d, n) }
+
+	var gaps []Gap
+	for _, g := range c.baseGaps() {
+		l := g.Len()
+		g.Start = rot(g.Start)
+		g.End = g.Start + l
+		if g.End > start+n {
+			h := g
+			g.End, h.Start, h.End = start+n, start, g.End-n
+			gaps = append(gaps, h)
+		}
+		gaps = append(gaps, g)
+	}
+	masks := c.masks
+	c.masks = nil
+	for _, r := range masks {
+		l := r.End - r.Start
+		r.Start = rot(r.Start)
+		c.addMask(r.Start, min(r.Start+l, start+n))
+		c.addMask(start, r.Start+l-n)
+	}
+	for _, p := range c.members {
+		p.start = rot(p.start)
+	}
+	for _, a := range c.features {
+		if a.anchor == nil {
+			l := a.end - a.start
+			a.start = rot(a.start)
+			a.end = a.start + l
+		}
+	}
+	return c.reset(start, start+n, gaps)
+}
+
+// mod returns a modulo n in the range [0, n).
+func mod(a, n int) int {
+	a %= n
+	if a < 0 {
+		a += n
+	}
+	return a
+}
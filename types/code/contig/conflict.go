@@ -0,0 +1,146 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/store/step"
+)
+
+// A Mismatch is a position at which two members of a Contig disagree. Letters are
+// given in the Contig's current orientation.
+type Mismatch struct {
+	Pos      int
+	Existing alphabet.Letter
+	Inserted alphabet.Letter
+}
+
+// A Conflict describes an overlap between two members of a Contig. Start and End
+// give the overlapping interval in the Contig's current coordinates. Existing is
+// the ID of the earlier inserted member and Inserted the ID of the later.
+// Mismatches holds the positions at which the members disagree, and is empty if
+// they agree over the whole overlap.
+type Conflict struct {
+	Start, End int
+	Existing   string
+	Inserted   string
+	Mismatches []Mismatch
+}
+
+// ConflictError is the error returned by Insert when an inserted sequence disagrees
+// with existing members of a strict Contig.
+type ConflictError struct {
+	ID        string
+	Conflicts []Conflict
+}
+
+func (e *ConflictError) Error() string {
+	var n int
+	for _, c := range e.Conflicts {
+		n += len(c.Mismatches)
+	}
+	return fmt.Sprintf("contig: %q conflicts with %d member(s) at %d position(s)", e.ID, len(e.Conflicts), n)
+}
+
+// Conflicts returns the disagreements between the members of the Contig. Each
+// member is compared with the members inserted before it. Conflicts are returned
+// ordered by the later member's insertion order and then the earlier member's.
+func (c *Contig) Conflicts() []Conflict {
+	var conflicts []Conflict
+	for _, o := range c.Overlaps() {
+		if len(o.Mismatches) != 0 {
+			conflicts = append(conflicts, o)
+		}
+	}
+	return conflicts
+}
+
+// Overlaps returns the overlaps between the members of the Contig, including
+// those in which the members agree. Overlaps are returned in the same order as
+// by Conflicts.
+func (c *Contig) Overlaps() []Conflict {
+	order := make(map[*placement]int, len(c.members))
+	for i, p := range c.members {
+		order[p] = i
+	}
+	var overlaps []Conflict
+	for i, p := range c.members {
+		overlaps = append(overlaps, c.overlaps(p, func(q *placement) bool { return order[q] < i })...)
+	}
+	return overlaps
+}
+
+// conflicts returns the disagreements between p and the members of the Contig
+// covering the same positions for which include returns true. The returned
+// conflicts are in insertion order of the existing members.
+func (c *Contig) conflicts(p *placement, include func(*placement) bool) []Conflict {
+	var conflicts []Conflict
+	for _, o := range c.overlaps(p, include) {
+		if len(o.Mismatches) != 0 {
+			conflicts = append(conflicts, o)
+		}
+	}
+	return conflicts
+}
+
+// overlaps returns the overlaps between p and the members of the Contig covering
+// the same positions for which include returns true. The returned overlaps are in
+// insertion order of the existing members.
+func (c *Contig) overlaps(p *placement, include func(*placement) bool) []Conflict {
+	found := make(map[*placement]*Conflict)
+	for _, r := range c.extents(p) {
+		from, to := max(r[0], c.vector.Start()), min(r[1], c.vector.End())
+		if from >= to {
+			continue
+		}
+		c.vector.DoRange(from, to, func(start, end int, e step.Equaler) {
+			ss, ok := e.(seqStep)
+			if !ok {
+				return
+			}
+			vs, ve := c.toView(start, end)
+			for _, q := range ss {
+				if q == p || !include(q) {
+					continue
+				}
+				cf, ok := found[q]
+				if !ok {
+					cf = &Conflict{Start: vs, End: ve, Existing: q.s.Name(), Inserted: p.s.Name()}
+					found[q] = cf
+				}
+				cf.Start, cf.End = min(cf.Start, vs), max(cf.End, ve)
+				for i := start; i < end; i++ {
+					lq, lp := q.at(i).L, p.at(i).L
+					if lq == lp {
+						continue
+					}
+					if c.view.complemented {
+						lq, lp = complement(c.Alpha, lq), complement(c.Alpha, lp)
+					}
+					cf.Mismatches = append(cf.Mismatches, Mismatch{Pos: c.pos(i), Existing: lq, Inserted: lp})
+				}
+			}
+		})
+	}
+	var overlaps []Conflict
+	for _, q := range c.members {
+		cf, ok := found[q]
+		if !ok {
+			continue
+		}
+		sort.Sort(byPos(cf.Mismatches))
+		overlaps = append(overlaps, *cf)
+	}
+	return overlaps
+}
+
+type byPos []Mismatch
+
+func (m byPos) Len() int           { return len(m) }
+func (m byPos) Less(i, j int) bool { return m[i].Pos < m[j].Pos }
+func (m byPos) Swap(i, j int)      { m[i], m[j] = m[j], m[i] }
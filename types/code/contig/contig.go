@@ -11,46 +11,258 @@ import (
 	"fmt"
 
 	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
 	"github.com/biogo/biogo/seq"
+	"github.com/biogo/biogo/seq/linear"
 	"github.com/biogo/biogo/seq/sequtils"
 	"github.com/biogo/biogo/util"
 	"github.com/biogo/store/step"
 )
 
-type seqStep struct {
-	seq.Sequence
+// Interface guarantees
+var (
+	_ feat.Feature = (*Contig)(nil)
+	_ seq.Sequence = (*Contig)(nil)
+)
+
+// frame records the orientation of a sequence relative to a reference.
+type frame struct {
+	reversed, complemented bool
+}
+
+// compose returns the frame resulting from applying g to f.
+func (f frame) compose(g frame) frame {
+	return frame{
+		reversed:     f.reversed != g.reversed,
+		complemented: f.complemented != g.complemented,
+	}
 }
 
+// complement returns the complement of l under a if a is an alphabet.Complementor,
+// and l otherwise.
+func complement(a alphabet.Alphabet, l alphabet.Letter) alphabet.Letter {
+	if c, ok := a.(alphabet.Complementor); ok {
+		if cl, ok := c.Complement(l); ok {
+			return cl
+		}
+	}
+	return l
+}
+
+// A placement is a member sequence held by a Contig. The member sequence is
+// not altered by the Contig; start is the position of the member in the base
+// coordinates of the Contig's step vector and the frame records the orientation
+// of the member's letters relative to the base coordinates. If the Contig is
+// circular, wrap is its length, and a member extending beyond the end of the
+// step vector continues from the vector's start.
+type placement struct {
+	s     seq.Sequence
+	start int
+	wrap  int
+	frame
+}
+
+func (p *placement) end() int { return p.start + p.s.Len() }
+
+// offset returns the position in the member, as inserted, of base position i.
+func (p *placement) offset(i int) int {
+	k := i - p.start
+	if k < 0 {
+		k += p.wrap
+	}
+	if p.reversed {
+		k = p.s.Len() - 1 - k
+	}
+	return k
+}
+
+// at returns the letter of the member at base position i.
+func (p *placement) at(i int) alphabet.QLetter {
+	l := p.s.At(p.s.Start() + p.offset(i))
+	if p.complemented {
+		l.L = complement(p.s.Alphabet(), l.L)
+	}
+	return l
+}
+
+func (p *placement) String() string { return fmt.Sprint(p.s) }
+
+// seqStep holds the members covering a step in insertion order.
+type seqStep []*placement
+
 // Equal returns a boolean indicating equality between the receiver
-// and the parameter. Equal will panic if the parameter does not satisfy
-// the seq.Sequence interface or is an ambig.
+// and the parameter. Two seqSteps are equal if they hold the same
+// members in the same order.
 func (s seqStep) Equal(e step.Equaler) bool {
-	switch e := e.(type) {
-	case ambig:
+	o, ok := e.(seqStep)
+	if !ok || len(s) != len(o) {
 		return false
-	case seqStep:
-		if s.Sequence == e.Sequence {
-			return true
+	}
+	for i := range s {
+		if s[i] != o[i] {
+			return false
 		}
-		return false
 	}
-	panic("contig: non-sequence types not handled")
+	return true
 }
 
-func (s seqStep) String() string { return fmt.Sprint(s.Sequence) }
+func (s seqStep) String() string {
+	if len(s) == 1 {
+		return s[0].String()
+	}
+	return fmt.Sprint([]*placement(s))
+}
 
 type ambig alphabet.Letter
 
-func (a ambig) Equal(e step.Equaler) bool   { return a == e.(ambig) }
+func (a ambig) Equal(e step.Equaler) bool {
+	o, ok := e.(ambig)
+	return ok && a == o
+}
 func (a ambig) Format(fs fmt.State, _ rune) { fs.Write([]byte{byte(a)}) }
 
+// A Policy specifies how the letter at a position covered by more than one
+// member sequence is resolved.
+type Policy int
+
+const (
+	LastWins    Policy = iota // The most recently inserted member provides the letter.
+	FirstWins                 // The earliest inserted member provides the letter.
+	BestQuality               // The member with the highest quality base provides the letter.
+	Majority                  // The most common letter among covering members is used.
+)
+
+func (p Policy) String() string {
+	switch p {
+	case LastWins:
+		return "LastWins"
+	case FirstWins:
+		return "FirstWins"
+	case BestQuality:
+		return "BestQuality"
+	case Majority:
+		return "Majority"
+	}
+	return fmt.Sprintf("Policy(%d)", int(p))
+}
+
+func parsePolicy(s string) (Policy, error) {
+	for _, p := range []Policy{LastWins, FirstWins, BestQuality, Majority} {
+		if p.String() == s {
+			return p, nil
+		}
+	}
+	return 0, fmt.Errorf("contig: unknown policy %q", s)
+}
+
+// resolve returns the letter at base position i given the members covering i.
+// Ties are broken in favour of the most recently inserted member.
+func (p Policy) resolve(i int, s seqStep) alphabet.QLetter {
+	switch p {
+	case FirstWins, LastWins, BestQuality:
+		return p.choose(i, s).at(i)
+	case Majority:
+		var (
+			count = make(map[alphabet.Letter]int, len(s))
+			qual  = make(map[alphabet.Letter]alphabet.Qphred, len(s))
+			best  alphabet.QLetter
+			n     int
+		)
+		for _, m := range s {
+			l := m.at(i)
+			count[l.L]++
+			if l.Q > qual[l.L] {
+				qual[l.L] = l.Q
+			}
+			if count[l.L] >= n {
+				best.L, n = l.L, count[l.L]
+			}
+		}
+		best.Q = qual[best.L]
+		return best
+	}
+	return s[len(s)-1].at(i)
+}
+
+// choose returns the member providing the letter at base position i given the
+// members covering i. Under the Majority policy the most recently inserted member
+// agreeing with the consensus letter is returned.
+func (p Policy) choose(i int, s seqStep) *placement {
+	switch p {
+	case FirstWins:
+		return s[0]
+	case BestQuality:
+		var (
+			best = s[0]
+			q    = best.at(i).Q
+		)
+		for _, m := range s[1:] {
+			if mq := m.at(i).Q; mq >= q {
+				best, q = m, mq
+			}
+		}
+		return best
+	case Majority:
+		l := p.resolve(i, s).L
+		for j := len(s) - 1; j >= 0; j-- {
+			if s[j].at(i).L == l {
+				return s[j]
+			}
+		}
+	}
+	return s[len(s)-1]
+}
+
+// A Contig is a sequence composed of member sequences placed on a step vector.
+// Every inserted member is retained; where members overlap, the letter at a
+// position is determined by the Contig's Policy.
+//
+// The orientation of a Contig is held as a view onto the step vector, so RevComp,
+// Reverse and SetOffset do not alter the layout or the member sequences. Positions
+// and letters are translated through the view on access. Materialize rewrites the
+// layout in the Contig's current orientation.
+//
+// A Contig must not be modified while it is read or modified by another goroutine.
+// A Builder accepts concurrent modifications and provides read-only snapshots
+// that may be read concurrently without locking.
 type Contig struct {
 	*seq.Annotation
+	policy Policy
+	strict bool
+
+	// groundQ is the quality of gap and ground state
+	// positions and encode is the quality encoding used
+	// for FASTQ output.
+	groundQ alphabet.Qphred
+	encode  alphabet.Encoding
+	members []*placement
+	index   map[string]*placement
+
+	features []*annot
+
+	// masks holds the masked intervals in base
+	// coordinates, sorted and merged, and masking
+	// specifies how they are rendered.
+	masks   []Interval
+	masking Masking
+
+	// view is the orientation of the Contig relative to
+	// the vector's base coordinates and shift is the
+	// translation from base coordinates to Contig
+	// coordinates.
+	view  frame
+	shift int
+
+	// frozen specifies that the Contig
+	// is a read-only snapshot.
+	frozen bool
+
 	vector *step.Vector
 }
 
 // New returns a new super contig sequence spanning the positions [0, l) and
 // using the provided alphabet's ambiguous letter as the step ground state.
+// The returned Contig resolves overlaps using the LastWins policy.
 func New(id string, l int, a alphabet.Alphabet) (*Contig, error) {
 	v, err := step.New(0, l, ambig(a.Ambiguous()))
 	if err != nil {
@@ -58,108 +270,564 @@ func New(id string, l int, a alphabet.Alphabet) (*Contig, error) {
 	}
 	return &Contig{
 		vector:     v,
+		index:      make(map[string]*placement),
+		groundQ:    seq.DefaultQphred,
+		encode:     seq.DefaultEncoding,
 		Annotation: &seq.Annotation{ID: id, Alpha: a},
 	}, nil
 }
 
+// SetPolicy sets the policy used to resolve positions covered by more than one member.
+func (c *Contig) SetPolicy(p Policy) {
+	c.checkWritable()
+	c.policy = p
+}
+
+// Policy returns the policy used to resolve positions covered by more than one member.
+func (c *Contig) Policy() Policy { return c.policy }
+
 // Relaxed sets the Contig's length restriction relaxation to the boolean r.
-func (c *Contig) Relaxed(r bool) { c.vector.Relaxed = r }
+// Circular and empty Contigs cannot be relaxed.
+func (c *Contig) Relaxed(r bool) {
+	c.checkWritable()
+	if c.vector == nil {
+		return
+	}
+	c.vector.Relaxed = r && !c.IsCircular()
+}
 
 // IsRelaxed returns whether the Contig allows insertion of contigs outside its length.
-func (c *Contig) IsRelaxed() bool { return c.vector.Relaxed }
+func (c *Contig) IsRelaxed() bool { return c.vector != nil && c.vector.Relaxed }
+
+// Strict sets whether the Contig rejects insertions that disagree with existing
+// members to the boolean s.
+func (c *Contig) Strict(s bool) {
+	c.checkWritable()
+	c.strict = s
+}
+
+// IsStrict returns whether the Contig rejects insertions that disagree with existing members.
+func (c *Contig) IsStrict() bool { return c.strict }
 
 // Joiner returns the ground state of the Contig.
-func (c *Contig) Joiner() alphabet.Letter { return alphabet.Letter(c.vector.Zero.(ambig)) }
+func (c *Contig) Joiner() alphabet.Letter {
+	if c.vector == nil {
+		return c.Alpha.Ambiguous()
+	}
+	return alphabet.Letter(c.vector.Zero.(ambig))
+}
+
+// pos returns the Contig position of base position i.
+func (c *Contig) pos(i int) int {
+	if c.view.reversed {
+		return c.shift - 1 - i
+	}
+	return i + c.shift
+}
+
+// base returns the base position of Contig position i.
+func (c *Contig) base(i int) int {
+	if c.view.reversed {
+		return c.shift - 1 - i
+	}
+	return i - c.shift
+}
+
+// toView returns the Contig interval corresponding to the base interval [start, end).
+func (c *Contig) toView(start, end int) (int, int) {
+	if c.view.reversed {
+		return c.shift - end, c.shift - start
+	}
+	return start + c.shift, end + c.shift
+}
+
+// toBase returns the base interval corresponding to the Contig interval [start, end).
+func (c *Contig) toBase(start, end int) (int, int) {
+	if c.view.reversed {
+		return c.shift - end, c.shift - start
+	}
+	return start - c.shift, end - c.shift
+}
+
+// interval returns the Contig interval corresponding to the base interval
+// [start, end). If the Contig is circular, the returned start is within the
+// Contig and the returned end may extend beyond the Contig's end.
+func (c *Contig) interval(start, end int) (int, int) {
+	start, end = c.toView(start, end)
+	if c.IsCircular() && start < c.Start() {
+		start, end = start+c.Len(), end+c.Len()
+	}
+	return start, end
+}
+
+// span returns the interval of the Contig covered by p.
+func (c *Contig) span(p *placement) (start, end int) {
+	return c.interval(p.start, p.end())
+}
+
+// normalize returns position i wrapped onto the Contig if the Contig is circular,
+// and i otherwise.
+func (c *Contig) normalize(i int) int {
+	if c.IsCircular() && c.Len() != 0 {
+		return c.Start() + mod(i-c.Start(), c.Len())
+	}
+	return i
+}
+
+// frameOf returns the orientation of p relative to the Contig.
+func (c *Contig) frameOf(p *placement) frame {
+	return p.frame.compose(c.view)
+}
+
+// do calls fn for each step of the Contig intersecting the interval [from, to)
+// in the order of the Contig's current orientation, passing Contig coordinates.
+func (c *Contig) do(from, to int, fn step.Operation) error {
+	if c.vector == nil {
+		return errEmpty
+	}
+	from, to = c.toBase(from, to)
+	if !c.view.reversed {
+		return c.vector.DoRange(from, to, func(start, end int, e step.Equaler) {
+			fn(c.pos(start), c.pos(end), e)
+		})
+	}
+	type span struct {
+		start, end int
+		e          step.Equaler
+	}
+	var steps []span
+	err := c.vector.DoRange(from, to, func(start, end int, e step.Equaler) {
+		steps = append(steps, span{start, end, e})
+	})
+	if err != nil {
+		return err
+	}
+	for i := len(steps) - 1; i >= 0; i-- {
+		start, end := c.toView(steps[i].start, steps[i].end)
+		fn(start, end, steps[i].e)
+	}
+	return nil
+}
 
 // Insert adds a sequence to the Contig. The sequence's alphabet must match the Contig's
 // alphabet. If the Contig is not relaxed an insertion beyond the range of the contig will
-// return an out of range error.
+// return an out of range error. Sequences already present in the Contig are retained
+// where the inserted sequence overlaps them. Member sequences are identified by their
+// Name, so the name of an inserted sequence must not match that of an existing member.
+// The sequence is placed at its Start position in the Contig's current orientation
+// and is not subsequently altered by the Contig. If the Contig is strict and the
+// sequence disagrees with an existing member, the Contig is not altered and a
+// *ConflictError describing the disagreements is returned.
 func (c *Contig) Insert(s seq.Sequence) error {
+	if c.frozen {
+		return errSnapshot
+	}
 	if s.Alphabet() != c.Alphabet() {
 		return errors.New("contig: alphabet mismatch")
 	}
-	if !c.vector.Relaxed && s.Start() < 0 || s.End() > s.End() {
+	if _, ok := c.index[s.Name()]; ok {
+		return errors.New("contig: duplicate member ID")
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	if c.IsCircular() {
+		if s.Len() > c.Len() {
+			return errors.New("contig: sequence longer than circular contig")
+		}
+	} else if !c.vector.Relaxed && (s.Start() < c.Start() || s.End() > c.End()) {
 		return errors.New("contig: sequence out of range")
 	}
-	c.insert(s)
-	return nil
+	p := c.newPlacement(s, s.Start(), frame{})
+	if c.strict {
+		conflicts := c.conflicts(p, func(*placement) bool { return true })
+		if len(conflicts) != 0 {
+			return &ConflictError{ID: s.Name(), Conflicts: conflicts}
+		}
+	}
+	return c.insert(p)
+}
+
+// newPlacement returns a placement of s at position start of the Contig with
+// the orientation f relative to the Contig's current orientation.
+func (c *Contig) newPlacement(s seq.Sequence, start int, f frame) *placement {
+	p := &placement{s: s, frame: f.compose(c.view)}
+	p.start, _ = c.toBase(start, start+s.Len())
+	if c.IsCircular() {
+		p.wrap = c.Len()
+		p.start = c.vector.Start() + mod(p.start-c.vector.Start(), p.wrap)
+	}
+	return p
+}
+
+// extents returns the base intervals covered by p.
+func (c *Contig) extents(p *placement) [][2]int {
+	return c.ranges(p.start, p.end(), p.wrap)
+}
+
+// ranges returns the base intervals covered by the base interval [start, end),
+// which continues from the start of the step vector beyond its end if wrap is
+// not zero.
+func (c *Contig) ranges(start, end, wrap int) [][2]int {
+	if vend := c.vector.End(); wrap != 0 && end > vend {
+		return [][2]int{{start, vend}, {c.vector.Start(), end - wrap}}
+	}
+	return [][2]int{{start, end}}
 }
 
-func (c *Contig) insert(s seq.Sequence) {
-	c.vector.SetRange(s.Start(), s.End(), seqStep{s})
+func (c *Contig) insert(p *placement) error {
+	for _, r := range c.extents(p) {
+		err := c.vector.ApplyRange(r[0], r[1], func(e step.Equaler) step.Equaler {
+			if e, ok := e.(seqStep); ok {
+				return append(e[:len(e):len(e)], p)
+			}
+			return seqStep{p}
+		})
+		if err != nil {
+			return err
+		}
+	}
+	c.members = append(c.members, p)
+	c.index[p.s.Name()] = p
+	return nil
 }
 
 // Start returns the Start position of the Contig.
-func (c *Contig) Start() int { return c.vector.Start() }
+func (c *Contig) Start() int {
+	if c.vector == nil {
+		return 0
+	}
+	start, _ := c.toView(c.vector.Start(), c.vector.End())
+	return start
+}
 
 // End returns the End position of the Contig.
-func (c *Contig) End() int { return c.vector.End() }
+func (c *Contig) End() int {
+	if c.vector == nil {
+		return 0
+	}
+	_, end := c.toView(c.vector.Start(), c.vector.End())
+	return end
+}
 
 // Len returns the length of the Contig.
-func (c *Contig) Len() int { return c.vector.Len() }
+func (c *Contig) Len() int {
+	if c.vector == nil {
+		return 0
+	}
+	return c.vector.Len()
+}
 
-// At returns the letter at position i of the Contig. At will panic if i is outside
-// the range of the Contig.
-func (c *Contig) At(i int) alphabet.QLetter {
-	l, err := c.vector.At(i)
+// SetOffset sets the start position of the Contig to o, moving all its members
+// by the same distance.
+func (c *Contig) SetOffset(o int) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	c.shift += o - c.Start()
+	return nil
+}
+
+// Normalize re-anchors the Contig so that it starts at position zero, moving its
+// members, gaps, masks and features by the same distance, and rewrites its layout
+// as described by Materialize. Normalize is typically used on a relaxed Contig
+// whose start has been extended below zero by insertions. It returns the distance
+// that positions were moved. Member sequences are not altered.
+func (c *Contig) Normalize() (shift int, err error) {
+	if c.frozen {
+		return 0, errSnapshot
+	}
+	if c.vector == nil {
+		return 0, nil
+	}
+	shift = -c.Start()
+	err = c.SetOffset(0)
 	if err != nil {
-		panic("err")
+		return 0, err
 	}
-	switch l := l.(type) {
+	return shift, c.Materialize()
+}
+
+// letter returns the letter at position i of the Contig given the step e covering i.
+func (c *Contig) letter(i int, e step.Equaler) alphabet.QLetter {
+	switch e := e.(type) {
 	case ambig:
-		return alphabet.QLetter{L: alphabet.Letter(l), Q: seq.DefaultQphred}
-	case seq.Sequence:
-		return l.At(i)
+		return alphabet.QLetter{L: alphabet.Letter(e), Q: c.groundQ}
+	case gapStep:
+		return alphabet.QLetter{L: c.Joiner(), Q: c.groundQ}
+	case seqStep:
+		b := c.base(i)
+		l := c.policy.resolve(b, e)
+		if c.view.complemented {
+			l.L = complement(c.Alpha, l.L)
+		}
+		if c.masking != NoMasking && c.isMasked(b) {
+			l.L = c.maskLetter(l.L)
+		}
+		return l
 	}
 	panic("contig: non-seq type not handled")
 }
 
-// Set sets the letter at postion i to l. If no sequence is present at the specified
-// position, Set is a no-op on the Contig and returns a non-nil error.
+// At returns the letter at position i of the Contig. If more than one member
+// covers i, the letter is resolved according to the Contig's Policy. At will
+// panic if i is outside the range of a linear Contig; positions of a circular
+// Contig wrap around its origin.
+func (c *Contig) At(i int) alphabet.QLetter {
+	if c.vector == nil {
+		panic(errEmpty)
+	}
+	i = c.normalize(i)
+	e, err := c.vector.At(c.base(i))
+	if err != nil {
+		panic(err)
+	}
+	return c.letter(i, e)
+}
+
+// Set sets the letter at postion i of every member covering i to l. If no sequence
+// is present at the specified position, Set is a no-op on the Contig and returns a
+// non-nil error.
 func (c *Contig) Set(i int, l alphabet.QLetter) error {
-	vs, err := c.vector.At(i)
+	if c.frozen {
+		return errSnapshot
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	j := c.base(c.normalize(i))
+	vs, err := c.vector.At(j)
 	if err != nil {
 		return err
 	}
 	switch vs := vs.(type) {
-	case ambig:
+	case ambig, gapStep:
 		return errors.New("contig: no sequence at specified position")
-	case seq.Sequence:
-		vs.Set(i, l)
+	case seqStep:
+		for _, p := range vs {
+			ml := l
+			if c.frameOf(p).complemented {
+				ml.L = complement(c.Alpha, ml.L)
+			}
+			err := p.s.Set(p.s.Start()+p.offset(j), ml)
+			if err != nil {
+				return err
+			}
+		}
 		return nil
 	}
 	panic("contig: non-seq type not handled")
 }
 
-// RevComp reverse complements the Contig and its contained sequences.
-func (c *Contig) RevComp() {
-	v, _ := step.New(0, c.vector.Len(), c.vector.Zero)
-	v.Relaxed = c.vector.Relaxed
-	c.vector.Do(func(start, end int, e step.Equaler) {
-		if e, ok := e.(seqStep); ok {
-			e.RevComp()
-			e.SetOffset(c.Len() - e.End())
-			v.SetRange(e.Start(), e.End(), e)
+// errEmpty is returned by methods that require a position of a Contig that has
+// zero length.
+var errEmpty = errors.New("contig: empty contig")
+
+// New returns an empty Contig with the same alphabet, ground state, overlap
+// policy, strictness and quality settings as the receiver. The returned Contig
+// has zero length until its slice is set with SetSlice. Until then, methods that
+// place members or gaps return an error and At panics.
+func (c *Contig) New() seq.Sequence {
+	return &Contig{
+		Annotation: &seq.Annotation{Alpha: c.Alpha},
+		policy:     c.policy,
+		strict:     c.strict,
+		groundQ:    c.groundQ,
+		encode:     c.encode,
+		masking:    c.masking,
+		index:      make(map[string]*placement),
+	}
+}
+
+// Clone returns a deep copy of the Contig. Each member sequence is cloned and
+// placed in the copy at the same position and in the same order.
+func (c *Contig) Clone() seq.Sequence {
+	cc := c.New().(*Contig)
+	cc.Annotation = c.CloneAnnotation()
+	if c.vector == nil {
+		return cc
+	}
+	cc.vector, _ = step.New(c.vector.Start(), c.vector.End(), c.vector.Zero)
+	cc.vector.Relaxed = c.vector.Relaxed
+	cc.view, cc.shift = c.view, c.shift
+	cc.masks = append([]Interval(nil), c.masks...)
+	for _, g := range c.baseGaps() {
+		cc.insertGap(g)
+	}
+	clones := make(map[*placement]*placement, len(c.members))
+	for _, p := range c.members {
+		pc := *p
+		pc.s = p.s.Clone()
+		clones[p] = &pc
+		cc.insert(&pc)
+	}
+	for _, a := range c.features {
+		ac := *a
+		if a.anchor != nil {
+			ac.anchor = clones[a.anchor]
+		}
+		cc.features = append(cc.features, &ac)
+	}
+	return cc
+}
+
+// Slice returns the letters of the Contig as an alphabet.Letters, with overlapping
+// positions resolved according to the Contig's Policy. If any member of the Contig
+// carries quality scores, the letters are returned as an alphabet.QLetters. The
+// returned slice is a copy and changes to it are not reflected in the Contig.
+func (c *Contig) Slice() alphabet.Slice {
+	if c.hasQuality() {
+		ql := make(alphabet.QLetters, 0, c.Len())
+		c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+			for i := start; i < end; i++ {
+				ql = append(ql, c.letter(i, e))
+			}
+		})
+		return ql
+	}
+	l := make(alphabet.Letters, 0, c.Len())
+	if c.vector == nil {
+		return l
+	}
+	c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+		for i := start; i < end; i++ {
+			l = append(l, c.letter(i, e).L)
 		}
 	})
-	c.vector = v
+	return l
+}
+
+// SetSlice replaces the members of the Contig with a single member holding the
+// letters in sl and named with the Contig's ID. The Contig's start position is
+// retained and its length becomes the length of sl. Features anchored to the
+// replaced members and features no longer within the Contig are removed.
+// SetSlice will panic if sl is neither an alphabet.Letters nor an
+// alphabet.QLetters, or if it is empty.
+func (c *Contig) SetSlice(sl alphabet.Slice) {
+	c.checkWritable()
+	var s seq.Sequence
+	switch sl := sl.(type) {
+	case alphabet.Letters:
+		s = linear.NewSeq(c.ID, sl, c.Alpha)
+	case alphabet.QLetters:
+		s = linear.NewQSeq(c.ID, sl, c.Alpha, c.encode)
+	default:
+		panic("contig: unsupported slice type")
+	}
+	start := c.Start()
+	s.SetOffset(start)
+	zero := step.Equaler(ambig(c.Alpha.Ambiguous()))
+	relaxed := false
+	var masks []Interval
+	if c.vector != nil {
+		zero, relaxed = c.vector.Zero, c.vector.Relaxed
+		c.rebaseFeatures()
+		masks = c.Masked()
+	}
+	v, err := step.New(start, start+sl.Len(), zero)
+	if err != nil {
+		panic(err)
+	}
+	v.Relaxed = relaxed
+	c.vector, c.members = v, nil
+	c.view, c.shift = frame{}, 0
+	c.index = make(map[string]*placement)
+	kept := c.features[:0]
+	for _, a := range c.features {
+		if a.anchor == nil && a.start >= v.Start() && a.end <= v.End() {
+			kept = append(kept, a)
+		}
+	}
+	c.features = kept
+	c.masks = nil
+	for _, r := range masks {
+		c.addMask(max(r.Start, v.Start()), min(r.End, v.End()))
+	}
+	c.insert(&placement{s: s, start: start})
+}
+
+// RevComp reverse complements the Contig. The operation does not alter the
+// Contig's layout or its member sequences; attached features are moved and
+// reoriented.
+func (c *Contig) RevComp() {
+	c.checkWritable()
+	c.flip(frame{reversed: true, complemented: true})
 	c.Strand = -c.Strand
-	fmt.Println()
 }
 
-// Reverse reverses the Contig and its contained sequences.
+// Reverse reverses the Contig. The operation does not alter the Contig's layout
+// or its member sequences; attached features are moved and reoriented.
 func (c *Contig) Reverse() {
-	v, _ := step.New(0, c.vector.Len(), c.vector.Zero)
+	c.checkWritable()
+	c.flip(frame{reversed: true})
+	c.Strand = seq.None
+}
+
+// flip applies the reorientation f to the Contig's view, retaining the Contig's
+// start and end positions.
+func (c *Contig) flip(f frame) {
+	if c.vector == nil {
+		return
+	}
+	if f.reversed {
+		c.shift = c.Start() + c.End() - c.shift
+	}
+	c.view = c.view.compose(f)
+}
+
+// Materialize rewrites the layout of the Contig so that its base coordinates
+// are those of its current orientation. Positions and letters of a materialized
+// Contig are accessed without translation. Member sequences are not altered.
+func (c *Contig) Materialize() error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if c.vector == nil || (c.view == frame{} && c.shift == 0) {
+		return nil
+	}
+	gaps := c.Gaps()
+	for _, p := range c.members {
+		p.start, _ = c.span(p)
+		p.frame = c.frameOf(p)
+	}
+	c.rebaseFeatures()
+	masks := c.Masked()
+	start, end := c.Start(), c.End()
+	c.view, c.shift = frame{}, 0
+	c.masks = nil
+	for _, r := range masks {
+		c.mask(r.Start, r.End)
+	}
+	return c.reset(start, end, gaps)
+}
+
+// rebuild reconstructs the Contig's step vector from its members and gaps,
+// retaining the current bounds of the vector.
+func (c *Contig) rebuild() error {
+	return c.reset(c.vector.Start(), c.vector.End(), c.baseGaps())
+}
+
+// reset reconstructs the Contig's step vector over the base interval [start, end)
+// from its members and the provided gaps in base coordinates. Gap positions covered
+// by a member are not marked.
+func (c *Contig) reset(start, end int, gaps []Gap) error {
+	v, err := step.New(start, end, c.vector.Zero)
+	if err != nil {
+		return err
+	}
 	v.Relaxed = c.vector.Relaxed
-	c.vector.Do(func(start, end int, e step.Equaler) {
-		if e, ok := e.(seqStep); ok {
-			e.Reverse()
-			e.SetOffset(c.Len() - e.End())
-			v.SetRange(e.Start(), e.End(), e)
+	members := c.members
+	c.vector, c.members = v, make([]*placement, 0, len(members))
+	for _, g := range gaps {
+		c.insertGap(g)
+	}
+	for _, p := range members {
+		err = c.insert(p)
+		if err != nil {
+			return err
 		}
-	})
-	c.vector = v
-	c.Strand = seq.None
+	}
+	return nil
 }
 
 func min(a, b int) int {
@@ -169,10 +837,24 @@ func min(a, b int) int {
 	return b
 }
 
+// layout returns a representation of the steps of the Contig in its current
+// orientation.
+func (c *Contig) layout() string {
+	if c.vector == nil {
+		return "[]"
+	}
+	var sb []string
+	c.do(c.Start(), c.End(), func(start, _ int, e step.Equaler) {
+		sb = append(sb, fmt.Sprintf("%d:%v", start, e))
+	})
+	sb = append(sb, fmt.Sprintf("%d:%v", c.End(), nil))
+	return fmt.Sprintf("%v", sb)
+}
+
 // Format is a fmt.Formatter helper. It provides support for the %v (with go syntax
-// representation), %s and %a (FASTA output). Note that %v representation takes no
-// account of overlapping inserted sequences and so will be misleading if overlaps
-// exist.
+// representation), %s, %a (FASTA output) and %q (FASTQ output). The %v representation
+// lists every member covering each step; %s, %a and %q render overlapping regions
+// according to the Contig's Policy.
 func (c *Contig) Format(fs fmt.State, cr rune) {
 	if c == nil {
 		fmt.Fprint(fs, "<nil>")
@@ -194,7 +876,7 @@ func (c *Contig) Format(fs fmt.State, cr rune) {
 			fmt.Fprintf(fs, "&%#v", *c)
 			return
 		} else {
-			fmt.Fprintf(fs, "%s", c.vector)
+			fmt.Fprint(fs, c.layout())
 		}
 		return
 	case 's':
@@ -208,30 +890,63 @@ func (c *Contig) Format(fs fmt.State, cr rune) {
 			fmt.Fprintf(fs, " %s", c.Desc)
 		}
 		fmt.Fprintln(fs)
+	case 'q':
+		fmt.Fprintf(fs, "%c%s", '@', c.ID)
+		if c.Desc != "" {
+			fmt.Fprintf(fs, " %s", c.Desc)
+		}
+		fmt.Fprintln(fs)
+		w = 0
 	default:
 		fmt.Fprintf(fs, "%%!%c(contig.Contig=%.10s)", cr, c)
 		return
 	}
+	if c.vector == nil {
+		return
+	}
 	lw := util.NewWrapper(fs, w, limit)
-	c.vector.DoRange(0, p,
+	c.do(c.Start(), c.Start()+p,
 		func(start, end int, e step.Equaler) {
 			switch e := e.(type) {
 			case seqStep:
-				if e.Start() != start || e.End() != end {
-					se := e.New()
-					sequtils.Truncate(se, e, start, end)
+				if len(e) > 1 || c.frameOf(e[0]) != (frame{}) || (c.masking != NoMasking && len(c.masks) != 0) {
+					for i := start; i < end; i++ {
+						lw.Write([]byte{byte(c.letter(i, e).L)})
+					}
+					break
+				}
+				m := e[0]
+				ms, me := c.span(m)
+				if start < ms {
+					// The step is the part of a circular
+					// member continuing from the origin.
+					start, end = start+c.Len(), end+c.Len()
+				}
+				if ms != start || me != end {
+					se := m.s.New()
+					off := m.s.Start() - ms
+					sequtils.Truncate(se, m.s, start+off, end+off)
 					fmt.Fprintf(lw, "%-s", se)
 					break
 				}
-				fmt.Fprintf(lw, "%-s", e.Sequence)
+				fmt.Fprintf(lw, "%-s", m.s)
 			case ambig:
 				eb := []byte{byte(e)}
 				for i := start; i < end; i++ {
 					lw.Write(eb)
 				}
+			case gapStep:
+				eb := []byte{byte(c.Joiner())}
+				for i := start; i < end; i++ {
+					lw.Write(eb)
+				}
 			}
 		},
 	)
+	if cr == 'q' {
+		fmt.Fprint(fs, "\n+\n")
+		c.formatQuality(util.NewWrapper(fs, 0, limit), p)
+	}
 	if pOk && p < c.Len() {
 		fmt.Fprint(fs, "...")
 	}
@@ -5,12 +5,19 @@
 package contig
 
 import (
+	"bytes"
+	"encoding/binary"
 	"fmt"
+	"io"
+	"strings"
+	"sync"
 	"testing"
 
 	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
 	"github.com/biogo/biogo/seq"
 	"github.com/biogo/biogo/seq/linear"
+	"github.com/biogo/biogo/seq/sequtils"
 
 	"gopkg.in/check.v1"
 )
@@ -108,3 +115,1576 @@ func (s *S) TestContig(c *check.C) {
 		c.Check(fmt.Sprintf("%s", con), check.Equals, t.rep.rc)
 	}
 }
+
+func qletters(s string, q alphabet.Qphred) []alphabet.QLetter {
+	ql := make([]alphabet.QLetter, len(s))
+	for i := range s {
+		ql[i] = alphabet.QLetter{L: alphabet.Letter(s[i]), Q: q}
+	}
+	return ql
+}
+
+func (s *S) TestPolicy(c *check.C) {
+	for i, t := range []struct {
+		policy Policy
+		str    string
+	}{
+		{policy: LastWins, str: "AACCGGGGnn"},
+		{policy: FirstWins, str: "AAAAAAGGnn"},
+		{policy: BestQuality, str: "AACCCCGGnn"},
+		{policy: Majority, str: "AACCAAGGnn"},
+	} {
+		con, err := New("test", 10, alphabet.DNA)
+		c.Assert(err, check.Equals, nil)
+		con.SetPolicy(t.policy)
+		for _, os := range []offsetSeq{
+			{linear.NewSeq("a", alphabet.BytesToLetters([]byte("AAAAAA")), alphabet.DNA), 0},
+			{linear.NewSeq("d", alphabet.BytesToLetters([]byte("AA")), alphabet.DNA), 4},
+			{linear.NewQSeq("b", qletters("CCCC", 60), alphabet.DNA, alphabet.Sanger), 2},
+			{linear.NewSeq("c", alphabet.BytesToLetters([]byte("GGGG")), alphabet.DNA), 4},
+		} {
+			os.seq.SetOffset(os.offset)
+			c.Check(con.Insert(os.seq), check.Equals, nil)
+		}
+		c.Check(fmt.Sprintf("%-s", con), check.Equals, t.str, check.Commentf("Test %d", i))
+	}
+}
+
+func (s *S) TestMembers(c *check.C) {
+	con, err := New("test", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("a", alphabet.BytesToLetters([]byte("AAAA")), alphabet.DNA), 0},
+		{linear.NewSeq("b", alphabet.BytesToLetters([]byte("CCCC")), alphabet.DNA), 2},
+		{linear.NewSeq("c", alphabet.BytesToLetters([]byte("GGG")), alphabet.DNA), 8},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(con.Insert(os.seq), check.Equals, nil)
+	}
+	dup := linear.NewSeq("a", alphabet.BytesToLetters([]byte("T")), alphabet.DNA)
+	c.Check(con.Insert(dup), check.ErrorMatches, "contig: duplicate member ID")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AACCCCnnGGGn")
+
+	m, ok := con.Lookup("b")
+	c.Check(ok, check.Equals, true)
+	c.Check(m.Start, check.Equals, 2)
+	c.Check(m.End, check.Equals, 6)
+	_, ok = con.Lookup("x")
+	c.Check(ok, check.Equals, false)
+
+	r, err := con.Remove("b")
+	c.Check(err, check.Equals, nil)
+	c.Check(r.Name(), check.Equals, "b")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AAAAnnnnGGGn")
+	_, err = con.Remove("b")
+	c.Check(err, check.ErrorMatches, "contig: no member with ID")
+
+	err = con.Replace("a", linear.NewSeq("a'", alphabet.BytesToLetters([]byte("TTTTT")), alphabet.DNA))
+	c.Check(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTTTTnnnGGGn")
+
+	r, err = con.RemoveAt(9)
+	c.Check(err, check.Equals, nil)
+	c.Check(r.Name(), check.Equals, "c")
+	_, err = con.RemoveAt(9)
+	c.Check(err, check.ErrorMatches, "contig: no sequence at specified position")
+
+	var ids []string
+	for _, m := range con.Members() {
+		ids = append(ids, m.Seq.Name())
+	}
+	c.Check(ids, check.DeepEquals, []string{"a'"})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTTTTnnnnnnn")
+
+	// Replacements keep the orientation of the replaced member.
+	rc, err := New("rc", 6, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	part, err := New("part", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(part.Insert(linear.NewSeq("x", alphabet.BytesToLetters([]byte("AACG")), alphabet.DNA)), check.Equals, nil)
+	c.Check(rc.Merge(part, 1, seq.Minus), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rc), check.Equals, "nCGTTn")
+	c.Check(rc.Replace("x", linear.NewSeq("y", alphabet.BytesToLetters([]byte("AAGG")), alphabet.DNA)), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rc), check.Equals, "nCCTTn")
+	m, ok = rc.Lookup("y")
+	c.Assert(ok, check.Equals, true)
+	c.Check(m.Strand, check.Equals, seq.Minus)
+
+	// Positions outside a circular Contig are wrapped.
+	circ, err := NewCircular("circ", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	g := linear.NewSeq("g", alphabet.BytesToLetters([]byte("GGG")), alphabet.DNA)
+	g.SetOffset(2)
+	c.Check(circ.Insert(g), check.Equals, nil)
+	r, err = circ.RemoveAt(13)
+	c.Check(err, check.Equals, nil)
+	c.Assert(r, check.Not(check.IsNil))
+	c.Check(r.Name(), check.Equals, "g")
+	c.Check(circ.Members(), check.HasLen, 0)
+}
+
+func (s *S) TestEmpty(c *check.C) {
+	con, err := New("test", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	e := con.New().(*Contig)
+	c.Check(e.Len(), check.Equals, 0)
+	c.Check(e.Joiner(), check.Equals, con.Joiner())
+	e.Relaxed(true)
+	c.Check(e.IsRelaxed(), check.Equals, false)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)
+	c.Check(e.Insert(a), check.ErrorMatches, "contig: empty contig")
+	c.Check(e.InsertGap(Gap{Start: 0, End: 1}), check.ErrorMatches, "contig: empty contig")
+	c.Check(e.RemoveGap(0), check.ErrorMatches, "contig: empty contig")
+	_, ok := e.GapAt(0)
+	c.Check(ok, check.Equals, false)
+	_, err = e.RemoveAt(0)
+	c.Check(err, check.ErrorMatches, "contig: empty contig")
+	_, _, _, err = e.ToMember(0)
+	c.Check(err, check.ErrorMatches, "contig: empty contig")
+	c.Check(e.Merge(con, 0, seq.Plus), check.ErrorMatches, "contig: empty contig")
+	c.Check(func() { e.At(0) }, check.PanicMatches, "contig: empty contig")
+	c.Check(e.Members(), check.HasLen, 0)
+
+	e.SetSlice(alphabet.Letters(alphabet.BytesToLetters([]byte("GG"))))
+	c.Check(fmt.Sprintf("%-s", e), check.Equals, "GG")
+}
+
+func (s *S) TestSequence(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("a", alphabet.BytesToLetters([]byte("AGTC")), alphabet.DNA), 1},
+		{linear.NewSeq("b", alphabet.BytesToLetters([]byte("ACG")), alphabet.DNA), 6},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(con.Insert(os.seq), check.Equals, nil)
+	}
+
+	cl := con.Clone().(*Contig)
+	cl.RevComp()
+	c.Check(fmt.Sprintf("%-s", cl), check.Equals, "nCGTnGACTn")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nAGTCnACGn")
+	c.Check(con.Slice(), check.DeepEquals, alphabet.Letters(alphabet.BytesToLetters([]byte("nAGTCnACGn"))))
+
+	tr := con.New()
+	c.Check(sequtils.Truncate(tr, con, 3, 8), check.Equals, nil)
+	c.Check(tr.Start(), check.Equals, 3)
+	c.Check(tr.Len(), check.Equals, 5)
+	c.Check(fmt.Sprintf("%-s", tr), check.Equals, "TCnAC")
+
+	c.Check(con.SetOffset(5), check.Equals, nil)
+	c.Check(con.Start(), check.Equals, 5)
+	m, _ := con.Lookup("b")
+	c.Check(m.Start, check.Equals, 11)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nAGTCnACGn")
+}
+
+const (
+	agpComponents = `>ctg1
+ACGTACGTAC
+>ctg2
+GGGACC
+`
+	agpLayout = "##agp-version\t2.1\n" +
+		"scf1\t1\t5\t1\tW\tctg1\t3\t7\t+\n" +
+		"scf1\t6\t8\t2\tN\t3\tscaffold\tyes\tpaired-ends\n" +
+		"scf1\t9\t12\t3\tW\tctg2\t2\t5\t-\n" +
+		"scf2\t1\t6\t1\tW\tctg2\t1\t6\t+\n"
+	agpRevComp = "##agp-version\t2.1\n" +
+		"scf1\t1\t4\t1\tW\tctg2\t2\t5\t+\n" +
+		"scf1\t5\t7\t2\tN\t3\tscaffold\tyes\tpaired-ends\n" +
+		"scf1\t8\t12\t3\tW\tctg1\t3\t7\t-\n"
+)
+
+func (s *S) TestAGP(c *check.C) {
+	comps, err := ReadComponents(strings.NewReader(agpComponents), alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(comps, check.HasLen, 2)
+
+	r := NewAGPReader(strings.NewReader(agpLayout), comps, alphabet.DNA)
+	var cons []*Contig
+	for {
+		con, err := r.Read()
+		if err == io.EOF {
+			break
+		}
+		c.Assert(err, check.Equals, nil)
+		cons = append(cons, con)
+	}
+	c.Assert(cons, check.HasLen, 2)
+	c.Check(fmt.Sprintf("%s", cons[0]), check.Equals, `"scf1" GTACGnnnGTCC`)
+	c.Check(fmt.Sprintf("%s", cons[1]), check.Equals, `"scf2" GGGACC`)
+	c.Check(cons[0].Gaps(), check.DeepEquals, []Gap{
+		{Start: 5, End: 8, Type: ScaffoldGap, Linkage: true, Evidence: []Evidence{PairedEnds}},
+	})
+
+	var buf bytes.Buffer
+	w := NewAGPWriter(&buf)
+	for _, con := range cons {
+		c.Check(w.Write(con), check.Equals, nil)
+	}
+	c.Check(buf.String(), check.Equals, agpLayout)
+
+	buf.Reset()
+	cons[0].RevComp()
+	c.Check(fmt.Sprintf("%s", cons[0]), check.Equals, `"scf1" GGACnnnCGTAC`)
+	g, ok := cons[0].GapAt(5)
+	c.Check(ok, check.Equals, true)
+	c.Check(g.Start, check.Equals, 4)
+	c.Check(g.End, check.Equals, 7)
+	c.Check(NewAGPWriter(&buf).Write(cons[0]), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, agpRevComp)
+
+	_, err = NewAGPReader(strings.NewReader("scf1\t1\t5\t1\tW\tctg3\t1\t5\t+\n"), comps, alphabet.DNA).Read()
+	c.Check(err, check.ErrorMatches, `contig: agp: no sequence for component "ctg3"`)
+
+	// Object coordinates are relative to the start of the Contig.
+	for _, off := range []int{100, -3} {
+		scf, err := NewAGPReader(strings.NewReader(agpLayout), comps, alphabet.DNA).Read()
+		c.Assert(err, check.Equals, nil)
+		var want bytes.Buffer
+		c.Check(NewAGPWriter(&want).Write(scf), check.Equals, nil)
+		c.Check(scf.SetOffset(off), check.Equals, nil)
+		buf.Reset()
+		c.Check(NewAGPWriter(&buf).Write(scf), check.Equals, nil)
+		c.Check(buf.String(), check.Equals, want.String())
+		got, err := NewAGPReader(&buf, comps, alphabet.DNA).Read()
+		c.Assert(err, check.Equals, nil)
+		c.Check(fmt.Sprintf("%s", got), check.Equals, `"scf1" GTACGnnnGTCC`)
+	}
+
+	// Components follow the member chosen by the Policy.
+	bq, err := New("bq", 6, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	bq.SetPolicy(BestQuality)
+	c.Check(bq.Insert(linear.NewQSeq("a", []alphabet.QLetter{{L: 'A', Q: 40}, {L: 'C', Q: 40}, {L: 'G', Q: 40}, {L: 'T', Q: 40}}, alphabet.DNA, alphabet.Sanger)), check.Equals, nil)
+	qb := linear.NewQSeq("b", []alphabet.QLetter{{L: 'T', Q: 10}, {L: 'T', Q: 10}, {L: 'T', Q: 10}, {L: 'T', Q: 10}}, alphabet.DNA, alphabet.Sanger)
+	qb.SetOffset(2)
+	c.Check(bq.Insert(qb), check.Equals, nil)
+	buf.Reset()
+	c.Check(NewAGPWriter(&buf).Write(bq), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, "##agp-version\t2.1\n"+
+		"bq\t1\t4\t1\tW\ta\t1\t4\t+\n"+
+		"bq\t5\t6\t2\tW\tb\t3\t4\t+\n")
+}
+
+func (s *S) TestGaps(c *check.C) {
+	con, err := New("test", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)
+	c.Check(con.Insert(a), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 3, End: 6}), check.ErrorMatches, "contig: gap overlaps member")
+	c.Check(con.InsertGap(Gap{Start: 10, End: 13}), check.ErrorMatches, "contig: gap out of range")
+	c.Check(con.InsertGap(Gap{Start: 4, End: 10, Type: ContigGap, Unknown: true}), check.Equals, nil)
+	c.Check(fmt.Sprintf("%v", con), check.Equals, `[0:"a" ACGT 4:<contig gap?> 10:n 12:<nil>]`)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACGTnnnnnnnn")
+
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("TT")), alphabet.DNA)
+	b.SetOffset(8)
+	c.Check(con.Insert(b), check.Equals, nil)
+	c.Check(con.Gaps(), check.DeepEquals, []Gap{{Start: 4, End: 8, Type: ContigGap, Unknown: true}})
+
+	con.Reverse()
+	c.Check(con.Gaps(), check.DeepEquals, []Gap{{Start: 4, End: 8, Type: ContigGap, Unknown: true}})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nnTTnnnnTGCA")
+	c.Check(con.RemoveGap(5), check.Equals, nil)
+	c.Check(con.Gaps(), check.HasLen, 0)
+	c.Check(con.RemoveGap(5), check.ErrorMatches, "contig: no gap at specified position")
+
+	// Positions of a circular Contig wrap.
+	circ, err := NewCircular("circ", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(circ.InsertGap(Gap{Start: 1, End: 4}), check.Equals, nil)
+	g, ok := circ.GapAt(12)
+	c.Check(ok, check.Equals, true)
+	c.Check([]int{g.Start, g.End}, check.DeepEquals, []int{1, 4})
+	_, ok = circ.GapAt(-8)
+	c.Check(ok, check.Equals, true)
+	c.Check(circ.RemoveGap(12), check.Equals, nil)
+	c.Check(circ.Gaps(), check.HasLen, 0)
+}
+
+func (s *S) TestMapping(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA), 1},
+		{linear.NewSeq("b", alphabet.BytesToLetters([]byte("GG")), alphabet.DNA), 7},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(con.Insert(os.seq), check.Equals, nil)
+	}
+
+	m, pos, strand, err := con.ToMember(2)
+	c.Check(err, check.Equals, nil)
+	c.Check(m.Name(), check.Equals, "a")
+	c.Check(pos, check.Equals, 1)
+	c.Check(strand, check.Equals, seq.Plus)
+	_, _, _, err = con.ToMember(5)
+	c.Check(err, check.ErrorMatches, "contig: no sequence at specified position")
+
+	con.RevComp()
+	m, pos, strand, err = con.ToMember(8)
+	c.Check(err, check.Equals, nil)
+	c.Check(m.Name(), check.Equals, "a")
+	c.Check(pos, check.Equals, 0)
+	c.Check(strand, check.Equals, seq.Minus)
+
+	i, strand, err := con.FromMember("a", 0)
+	c.Check(err, check.Equals, nil)
+	c.Check(i, check.Equals, 8)
+	c.Check(strand, check.Equals, seq.Minus)
+
+	segs, err := con.ToMembers(0, 10)
+	c.Check(err, check.Equals, nil)
+	c.Assert(segs, check.HasLen, 2)
+	c.Check(segs[0].Member.Name(), check.Equals, "b")
+	c.Check([]int{segs[0].Start, segs[0].End, segs[0].MemberStart, segs[0].MemberEnd}, check.DeepEquals, []int{1, 3, 0, 2})
+	c.Check(segs[1].Member.Name(), check.Equals, "a")
+	c.Check([]int{segs[1].Start, segs[1].End, segs[1].MemberStart, segs[1].MemberEnd}, check.DeepEquals, []int{5, 9, 0, 4})
+
+	seg, err := con.FromMemberInterval("a", 1, 3)
+	c.Check(err, check.Equals, nil)
+	c.Check([]int{seg.Start, seg.End}, check.DeepEquals, []int{6, 8})
+	c.Check(seg.Strand, check.Equals, seq.Minus)
+
+	// Segments follow the member chosen by the Policy at each position.
+	bq, err := New("bq", 5, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	bq.SetPolicy(BestQuality)
+	qa := linear.NewQSeq("a", []alphabet.QLetter{{L: 'A', Q: 40}, {L: 'C', Q: 40}, {L: 'G', Q: 5}, {L: 'T', Q: 40}}, alphabet.DNA, alphabet.Sanger)
+	c.Check(bq.Insert(qa), check.Equals, nil)
+	qb := linear.NewQSeq("b", []alphabet.QLetter{{L: 'T', Q: 20}, {L: 'T', Q: 20}, {L: 'T', Q: 20}, {L: 'T', Q: 20}}, alphabet.DNA, alphabet.Sanger)
+	qb.SetOffset(1)
+	c.Check(bq.Insert(qb), check.Equals, nil)
+	m, _, _, err = bq.ToMember(1)
+	c.Check(err, check.Equals, nil)
+	c.Check(m.Name(), check.Equals, "a")
+	segs, err = bq.ToMembers(0, 5)
+	c.Check(err, check.Equals, nil)
+	var got []string
+	for _, sg := range segs {
+		got = append(got, fmt.Sprintf("%s:%d-%d", sg.Member.Name(), sg.Start, sg.End))
+		l := bq.At(sg.Start).L
+		c.Check(sg.Member.At(sg.Member.Start()+sg.MemberStart).L, check.Equals, l)
+	}
+	c.Check(got, check.DeepEquals, []string{"a:0-2", "b:2-3", "a:3-4", "b:4-5"})
+}
+
+type testFeature struct {
+	name, desc string
+	start, end int
+	orient     feat.Orientation
+}
+
+func (f testFeature) Start() int                    { return f.start }
+func (f testFeature) End() int                      { return f.end }
+func (f testFeature) Len() int                      { return f.end - f.start }
+func (f testFeature) Name() string                  { return f.name }
+func (f testFeature) Description() string           { return f.desc }
+func (f testFeature) Location() feat.Feature        { return nil }
+func (f testFeature) Orientation() feat.Orientation { return f.orient }
+
+func (s *S) TestFeatures(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA), 0},
+		{linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGA")), alphabet.DNA), 6},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(con.Insert(os.seq), check.Equals, nil)
+	}
+	c.Check(con.Annotate(testFeature{name: "g1", desc: "gene", start: 1, end: 8, orient: feat.Forward}), check.Equals, nil)
+	c.Check(con.AnnotateMember("b", testFeature{name: "r1", desc: "repeat", start: 0, end: 2, orient: feat.Reverse}), check.Equals, nil)
+	c.Check(con.Annotate(testFeature{name: "x", start: 8, end: 11}), check.ErrorMatches, "contig: feature out of range")
+
+	con.RevComp()
+	var buf bytes.Buffer
+	c.Check(NewGFFWriter(&buf).Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, "##gff-version 3\n"+
+		"test\t.\tgene\t3\t9\t.\t-\t.\tID=g1\n"+
+		"test\t.\trepeat\t3\t4\t.\t+\t.\tID=r1;anchor=b\n")
+
+	_, err = con.Remove("b")
+	c.Check(err, check.Equals, nil)
+	p := con.Features()
+	c.Assert(p, check.HasLen, 1)
+	c.Check(p[0].Feature.Name(), check.Equals, "g1")
+	c.Check([]int{p[0].Start, p[0].End}, check.DeepEquals, []int{2, 9})
+}
+
+func (s *S) TestGFA(c *check.C) {
+	for _, t := range []struct {
+		version int
+		gfa     string
+	}{
+		{
+			version: 1,
+			gfa: "H\tVN:Z:1.2\n" +
+				"S\ta\tACGTAC\tLN:i:6\n" +
+				"S\td\tGT\tLN:i:2\n" +
+				"S\tb\tTTTGT\tLN:i:5\n" +
+				"S\tc\tGGG\tLN:i:3\n" +
+				"C\ta\t+\td\t+\t2\t2M\n" +
+				"L\ta\t+\tb\t-\t2M\n" +
+				"J\tb\t-\tc\t+\t*\n" +
+				"P\tscf\ta+,b-;c+\t*\n",
+		},
+		{
+			version: 2,
+			gfa: "H\tVN:Z:2.0\n" +
+				"S\ta\t6\tACGTAC\n" +
+				"S\td\t2\tGT\n" +
+				"S\tb\t5\tTTTGT\n" +
+				"S\tc\t3\tGGG\n" +
+				"E\te1\ta+\td+\t2\t4\t0\t2$\t2M\n" +
+				"E\te2\ta+\tb-\t4\t6$\t3\t5$\t2M\n" +
+				"G\tg3\tb-\tc+\t100\t*\n" +
+				"O\tscf\ta+ b- c+\n",
+		},
+	} {
+		cons, err := ReadGFA(strings.NewReader(t.gfa), alphabet.DNA)
+		c.Assert(err, check.Equals, nil)
+		c.Assert(cons, check.HasLen, 1)
+		con := cons[0]
+		c.Check(con.ID, check.Equals, "scf")
+		c.Check(con.Len(), check.Equals, 112)
+		c.Check(fmt.Sprintf("%.12s", con), check.Equals, `"scf" ACGTACAAAnnn...`)
+		g := con.Gaps()
+		c.Assert(g, check.HasLen, 1)
+		c.Check([]int{g[0].Start, g[0].End}, check.DeepEquals, []int{9, 109})
+		c.Check(g[0].Unknown, check.Equals, t.version == 1)
+
+		var buf bytes.Buffer
+		w, err := NewGFAWriter(&buf, t.version)
+		c.Assert(err, check.Equals, nil)
+		c.Check(w.Write(con), check.Equals, nil)
+		c.Check(buf.String(), check.Equals, t.gfa, check.Commentf("GFA %d", t.version))
+	}
+
+	con, err := New("rc", 6, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("AACG")), alphabet.DNA)
+	c.Check(con.Insert(a), check.Equals, nil)
+	con.RevComp()
+	var buf bytes.Buffer
+	w, _ := NewGFAWriter(&buf, 1)
+	c.Check(w.Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, "H\tVN:Z:1.2\nS\ta\tAACG\tLN:i:4\nP\trc\ta-\t*\n")
+
+	// Repeated visits to a segment are distinct members.
+	cons, err := ReadGFA(strings.NewReader("S\ta\tACGT\nS\tb\tGG\nP\tp\ta+,b+,a+,a-\t*\n"), alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Assert(cons, check.HasLen, 1)
+	c.Check(fmt.Sprintf("%-s", cons[0]), check.Equals, "ACGTGGACGTACGT")
+	var ids []string
+	for _, m := range cons[0].Members() {
+		ids = append(ids, m.Seq.Name())
+	}
+	c.Check(ids, check.DeepEquals, []string{"a", "b", "a#2", "a#3"})
+
+	for _, t := range []struct {
+		gfa string
+		err string
+	}{
+		{gfa: "S\ta\tACGT\nL\ta\t\tb\t+\t*\n", err: `contig: gfa line 2: invalid orientation ""`},
+		{gfa: "S\ta\tACGT\nC\ta\t+\tb\tx\t0\t*\n", err: `contig: gfa line 2: invalid orientation "x"`},
+		{gfa: "S\ta\tACGT\nP\tp\ta+,-\t*\n", err: `contig: gfa line 2: invalid segment reference "-"`},
+		{gfa: "S\ta\tACGT\nP\tp\t\t*\n", err: `contig: gfa line 2: no segments in path "p"`},
+		{gfa: "S\ta\t*\tLN:i:4\n", err: `contig: gfa line 1: no sequence for segment "a"`},
+		{gfa: "H\tVN:Z:2.0\nS\ta\t4\tACGT\nE\te\ta\tb+\t0\t1\t0\t1\t*\n", err: `contig: gfa line 3: invalid segment reference "a"`},
+		{gfa: "H\tVN:Z:2.0\nS\ta\t4\tACGT\nG\tg\ta+\t\t10\t*\n", err: `contig: gfa line 3: invalid segment reference ""`},
+		{gfa: "H\tVN:Z:2.0\nO\tp\t\n", err: `contig: gfa line 2: no segments in path "p"`},
+	} {
+		_, err := ReadGFA(strings.NewReader(t.gfa), alphabet.DNA)
+		c.Check(err, check.ErrorMatches, t.err, check.Commentf("%q", t.gfa))
+	}
+}
+
+func (s *S) TestOrientation(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("AGTC")), alphabet.DNA)
+	a.SetOffset(1)
+	c.Check(con.Insert(a), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 5, End: 7}), check.Equals, nil)
+
+	con.RevComp()
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nnnnnGACTn")
+	c.Check(fmt.Sprintf("%-s", a), check.Equals, "AGTC")
+	c.Check(a.Start(), check.Equals, 1)
+	c.Check(con.Gaps(), check.DeepEquals, []Gap{{Start: 3, End: 5}})
+
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("CC")), alphabet.DNA)
+	b.SetOffset(1)
+	c.Check(con.Insert(b), check.Equals, nil)
+	c.Check(con.Set(6, alphabet.QLetter{L: 'T'}), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nCCnnGTCTn")
+	c.Check(fmt.Sprintf("%-s", a), check.Equals, "AGAC")
+	m, ok := con.Lookup("b")
+	c.Check(ok, check.Equals, true)
+	c.Check(m.Start, check.Equals, 1)
+	c.Check(m.Strand, check.Equals, seq.Plus)
+
+	c.Check(con.SetOffset(4), check.Equals, nil)
+	c.Check(con.Start(), check.Equals, 4)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nCCnnGTCTn")
+
+	con.RevComp()
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nAGACnnGGn")
+	c.Check(con.Start(), check.Equals, 4)
+	m, _ = con.Lookup("b")
+	c.Check(m.Start, check.Equals, 11)
+	c.Check(m.Strand, check.Equals, seq.Minus)
+
+	c.Check(con.Materialize(), check.Equals, nil)
+	c.Check(fmt.Sprintf("%v", con), check.Equals, `[4:n 5:"a" AGAC 9:<scaffold gap> 11:"b" CC 13:n 14:<nil>]`)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nAGACnnGGn")
+	c.Check(fmt.Sprintf("%-s", b), check.Equals, "CC")
+}
+
+func (s *S) TestStats(c *check.C) {
+	a, err := New("a", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	b, err := New("b", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, t := range []struct {
+		con *Contig
+		os  offsetSeq
+	}{
+		{a, offsetSeq{linear.NewSeq("m1", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA), 0}},
+		{a, offsetSeq{linear.NewSeq("m2", alphabet.BytesToLetters([]byte("GG")), alphabet.DNA), 6}},
+		{b, offsetSeq{linear.NewSeq("m3", alphabet.BytesToLetters([]byte("ATAT")), alphabet.DNA), 0}},
+	} {
+		t.os.seq.SetOffset(t.os.offset)
+		c.Check(t.con.Insert(t.os.seq), check.Equals, nil)
+	}
+	c.Check(a.InsertGap(Gap{Start: 4, End: 6}), check.Equals, nil)
+	a.RevComp()
+
+	st := Summarize([]*Contig{b, a}, 24)
+	c.Check(st.N50, check.Equals, 10)
+	c.Check(st.L50, check.Equals, 1)
+	c.Check(st.NG50, check.Equals, 4)
+	c.Check(st.LG50, check.Equals, 2)
+
+	var buf bytes.Buffer
+	c.Check(st.WriteTSV(&buf), check.Equals, nil)
+	c.Check(buf.String(), check.Equals,
+		"contigs\tmembers\tlength\tmin\tmax\tn50\tl50\tgenome_size\tng50\tlg50\tgaps\tgap_length\tgc\tambiguous\n"+
+			"2\t3\t14\t4\t10\t10\t1\t24\t4\t2\t1\t2\t0.4000\t0.2857\n")
+
+	// Ground state between members is counted as gap, as for AGP,
+	// but not ground state at the ends of a Contig.
+	g, err := New("g", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("m4", alphabet.BytesToLetters([]byte("AC")), alphabet.DNA), 1},
+		{linear.NewSeq("m5", alphabet.BytesToLetters([]byte("GT")), alphabet.DNA), 5},
+		{linear.NewSeq("m6", alphabet.BytesToLetters([]byte("TT")), alphabet.DNA), 9},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(g.Insert(os.seq), check.Equals, nil)
+	}
+	c.Check(g.InsertGap(Gap{Start: 7, End: 8}), check.Equals, nil)
+	st = Summarize([]*Contig{g}, 0)
+	c.Check(st.Gaps, check.Equals, 3)
+	c.Check(st.GapLength, check.Equals, 4)
+
+	buf.Reset()
+	c.Check(Summarize(nil, 0).WriteJSON(&buf), check.Equals, nil)
+	c.Check(buf.String(), check.Equals,
+		`{"contigs":0,"members":0,"length":0,"min":0,"max":0,"n50":0,"l50":0,"gaps":0,"gap_length":0,"gc":0,"ambiguous":0}`+"\n")
+}
+
+func (s *S) TestCircular(c *check.C) {
+	con, err := NewCircular("plasmid", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	con.Relaxed(true)
+	c.Check(con.IsRelaxed(), check.Equals, false)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTA")), alphabet.DNA)
+	a.SetOffset(7)
+	c.Check(con.Insert(a), check.Equals, nil)
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGG")), alphabet.DNA)
+	b.SetOffset(13)
+	c.Check(con.Insert(b), check.Equals, nil)
+	long := linear.NewSeq("long", alphabet.BytesToLetters([]byte("ACGTACGTACG")), alphabet.DNA)
+	c.Check(con.Insert(long), check.ErrorMatches, "contig: sequence longer than circular contig")
+	c.Check(con.SetConformation(feat.Linear), check.ErrorMatches, "contig: cannot change conformation of populated contig")
+
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TAnGGGnACG")
+	c.Check(con.At(17).L, check.Equals, alphabet.Letter('A'))
+	c.Check(con.At(-1).L, check.Equals, alphabet.Letter('G'))
+	m, _ := con.Lookup("a")
+	c.Check(m.Start, check.Equals, 7)
+	c.Check(m.End, check.Equals, 12)
+	_, pos, _, err := con.ToMember(1)
+	c.Check(err, check.Equals, nil)
+	c.Check(pos, check.Equals, 4)
+	i, _, err := con.FromMember("a", 3)
+	c.Check(err, check.Equals, nil)
+	c.Check(i, check.Equals, 0)
+
+	tr := linear.NewSeq("", nil, alphabet.DNA)
+	c.Check(sequtils.Truncate(tr, con, 7, 2), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", tr), check.Equals, "ACGTA")
+
+	con.RevComp()
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "CGTnCCCnTA")
+	m, _ = con.Lookup("a")
+	c.Check(m.Start, check.Equals, 8)
+	c.Check(m.End, check.Equals, 13)
+
+	c.Check(con.Rotate(8), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TACGTnCCCn")
+	m, _ = con.Lookup("a")
+	c.Check(m.Start, check.Equals, 0)
+
+	_, err = con.Remove("a")
+	c.Check(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nnnnnnCCCn")
+
+	lin, err := New("linear", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(lin.Rotate(2), check.ErrorMatches, "contig: cannot rotate linear contig")
+}
+
+func (s *S) TestConflicts(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	out := linear.NewSeq("out", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)
+	out.SetOffset(8)
+	c.Check(con.Insert(out), check.ErrorMatches, "contig: sequence out of range")
+
+	con.Strict(true)
+	c.Check(con.IsStrict(), check.Equals, true)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTAC")), alphabet.DNA)
+	c.Check(con.Insert(a), check.Equals, nil)
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("TACG")), alphabet.DNA)
+	b.SetOffset(3)
+	c.Check(con.Insert(b), check.Equals, nil)
+
+	d := linear.NewSeq("d", alphabet.BytesToLetters([]byte("GAAC")), alphabet.DNA)
+	d.SetOffset(2)
+	err = con.Insert(d)
+	c.Check(err, check.ErrorMatches, `contig: "d" conflicts with 2 member\(s\) at 2 position\(s\)`)
+	ce, ok := err.(*ConflictError)
+	c.Assert(ok, check.Equals, true)
+	c.Check(ce.Conflicts, check.DeepEquals, []Conflict{
+		{Start: 2, End: 6, Existing: "a", Inserted: "d", Mismatches: []Mismatch{{Pos: 3, Existing: 'T', Inserted: 'A'}}},
+		{Start: 3, End: 6, Existing: "b", Inserted: "d", Mismatches: []Mismatch{{Pos: 3, Existing: 'T', Inserted: 'A'}}},
+	})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACGTACGnnn")
+	_, ok = con.Lookup("d")
+	c.Check(ok, check.Equals, false)
+
+	con.Strict(false)
+	c.Check(con.Insert(d), check.Equals, nil)
+	con.RevComp()
+	c.Check(con.Conflicts(), check.DeepEquals, []Conflict{
+		{Start: 4, End: 8, Existing: "a", Inserted: "d", Mismatches: []Mismatch{{Pos: 6, Existing: 'A', Inserted: 'T'}}},
+		{Start: 4, End: 7, Existing: "b", Inserted: "d", Mismatches: []Mismatch{{Pos: 6, Existing: 'A', Inserted: 'T'}}},
+	})
+	c.Check(con.Overlaps(), check.DeepEquals, []Conflict{
+		{Start: 4, End: 7, Existing: "a", Inserted: "b"},
+		{Start: 4, End: 8, Existing: "a", Inserted: "d", Mismatches: []Mismatch{{Pos: 6, Existing: 'A', Inserted: 'T'}}},
+		{Start: 4, End: 7, Existing: "b", Inserted: "d", Mismatches: []Mismatch{{Pos: 6, Existing: 'A', Inserted: 'T'}}},
+	})
+}
+
+func (s *S) TestSubcontig(c *check.C) {
+	con, err := New("test", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTAC")), alphabet.DNA), 1},
+		{linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGT")), alphabet.DNA), 9},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(con.Insert(os.seq), check.Equals, nil)
+	}
+	c.Check(con.InsertGap(Gap{Start: 7, End: 9, Type: ContigGap}), check.Equals, nil)
+	c.Check(con.AnnotateMember("a", testFeature{name: "f1", desc: "repeat", start: 3, end: 5}), check.Equals, nil)
+	c.Check(con.Annotate(testFeature{name: "f2", desc: "repeat", start: 9, end: 11}), check.Equals, nil)
+	con.RevComp()
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACCnnGTACGTn")
+
+	_, err = con.Subcontig(4, 13)
+	c.Check(err, check.ErrorMatches, "contig: interval out of range")
+	sub, err := con.Subcontig(2, 11)
+	c.Assert(err, check.Equals, nil)
+	c.Check(sub.ID, check.Equals, "test")
+	c.Check(sub.Start(), check.Equals, 0)
+	c.Check(fmt.Sprintf("%-s", sub), check.Equals, "CnnGTACGT")
+	c.Check(sub.Gaps(), check.DeepEquals, []Gap{{Start: 1, End: 3, Type: ContigGap}})
+	var ms []string
+	for _, m := range sub.Members() {
+		ms = append(ms, fmt.Sprintf("%s:%d-%d%v %-s", m.Seq.Name(), m.Start, m.End, m.Strand, m.Seq))
+	}
+	c.Check(ms, check.DeepEquals, []string{"a:3-9- ACGTAC", "b:0-1- G"})
+	f := sub.Features()
+	c.Assert(f, check.HasLen, 1)
+	c.Check(f[0].Start, check.Equals, 4)
+	c.Check(f[0].End, check.Equals, 6)
+	c.Check(f[0].Anchor, check.Equals, "a")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACCnnGTACGTn")
+
+	circ, err := NewCircular("plasmid", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTA")), alphabet.DNA)
+	a.SetOffset(7)
+	c.Check(circ.Insert(a), check.Equals, nil)
+	sub, err = circ.Subcontig(8, 12)
+	c.Assert(err, check.Equals, nil)
+	c.Check(sub.IsCircular(), check.Equals, false)
+	c.Check(fmt.Sprintf("%-s", sub), check.Equals, "CGTA")
+	sub, err = circ.Subcontig(7, 17)
+	c.Assert(err, check.Equals, nil)
+	c.Check(sub.IsCircular(), check.Equals, true)
+	c.Check(sub.Start(), check.Equals, 0)
+	c.Check(fmt.Sprintf("%-s", sub), check.Equals, "ACGTAnnnnn")
+
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("ACGTACGTA")), alphabet.DNA)
+	_, err = circ.Remove("a")
+	c.Check(err, check.Equals, nil)
+	b.SetOffset(5)
+	c.Check(circ.Insert(b), check.Equals, nil)
+	_, err = circ.Subcontig(3, 6)
+	c.Check(err, check.ErrorMatches, "contig: interval divides member")
+}
+
+func (s *S) TestJoin(c *check.C) {
+	a, err := New("A", 5, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(a.Insert(linear.NewSeq("a1", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)), check.Equals, nil)
+	b, err := New("B", 6, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, os := range []offsetSeq{
+		{linear.NewSeq("b1", alphabet.BytesToLetters([]byte("GGA")), alphabet.DNA), 0},
+		{linear.NewSeq("b2", alphabet.BytesToLetters([]byte("TC")), alphabet.DNA), 4},
+	} {
+		os.seq.SetOffset(os.offset)
+		c.Check(b.Insert(os.seq), check.Equals, nil)
+	}
+	c.Check(b.InsertGap(Gap{Start: 3, End: 4, Unknown: true}), check.Equals, nil)
+	c.Check(b.Annotate(testFeature{name: "f", desc: "gene", start: 0, end: 2, orient: feat.Forward}), check.Equals, nil)
+
+	j, err := Join("S", Part{Contig: a}, Part{Contig: b, Strand: seq.Minus, Gap: Gap{End: 3, Linkage: true}})
+	c.Assert(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%s", j), check.Equals, `"S" ACGTnnnnGAnTCC`)
+	c.Check(j.Gaps(), check.DeepEquals, []Gap{{Start: 5, End: 8, Linkage: true}, {Start: 10, End: 11, Unknown: true}})
+	m, ok := j.Lookup("b1")
+	c.Check(ok, check.Equals, true)
+	c.Check(m.Start, check.Equals, 11)
+	c.Check(m.Strand, check.Equals, seq.Minus)
+	c.Check(fmt.Sprintf("%-s", b), check.Equals, "GGAnTC")
+	f := j.Features()
+	c.Assert(f, check.HasLen, 1)
+	c.Check(f[0].Start, check.Equals, 12)
+	c.Check(f[0].Orientation, check.Equals, feat.Reverse)
+
+	circ, err := NewCircular("C", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	w := linear.NewSeq("w", alphabet.BytesToLetters([]byte("AAAACC")), alphabet.DNA)
+	w.SetOffset(7)
+	c.Check(circ.Insert(w), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", circ), check.Equals, "ACCnnnnAAA")
+	tail, err := New("T", 5, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(tail.Insert(linear.NewSeq("t", alphabet.BytesToLetters([]byte("TTTTT")), alphabet.DNA)), check.Equals, nil)
+	_, err = Join("J", Part{Contig: circ}, Part{Contig: tail})
+	c.Check(err, check.ErrorMatches, `contig: member "w" spans the origin of "C"`)
+	c.Check(circ.Rotate(7), check.Equals, nil)
+	j, err = Join("J", Part{Contig: circ}, Part{Contig: tail})
+	c.Assert(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", j), check.Equals, "AAAACCnnnnTTTTT")
+
+	empty := a.New().(*Contig)
+	j, err = Join("E", Part{Contig: empty}, Part{Contig: a}, Part{Contig: empty, Gap: Gap{End: 2}})
+	c.Assert(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%s", j), check.Equals, `"E" ACGTnnn`)
+
+	_, err = Join("S", Part{Contig: a}, Part{Contig: a})
+	c.Check(err, check.ErrorMatches, "contig: duplicate member ID")
+	r, err := New("R", 4, alphabet.RNA)
+	c.Assert(err, check.Equals, nil)
+	_, err = Join("S", Part{Contig: a}, Part{Contig: r})
+	c.Check(err, check.ErrorMatches, "contig: alphabet mismatch")
+
+	t, err := New("T", 8, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	t.Strict(true)
+	c.Check(t.Insert(linear.NewSeq("t1", alphabet.BytesToLetters([]byte("ACCA")), alphabet.DNA)), check.Equals, nil)
+	c.Check(t.Merge(a, 5, seq.Plus), check.ErrorMatches, "contig: sequence out of range")
+	err = t.Merge(a, 0, seq.Plus)
+	c.Check(err, check.FitsTypeOf, &ConflictError{})
+	c.Check(fmt.Sprintf("%-s", t), check.Equals, "ACCAnnnn")
+	c.Check(t.Merge(a, 3, seq.Minus), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", t), check.Equals, "ACCAACGT")
+}
+
+func (s *S) TestPileup(c *check.C) {
+	con, err := New("test", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTAC")), alphabet.DNA)
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("TAC")), alphabet.DNA)
+	b.SetOffset(3)
+	d := linear.NewSeq("d", alphabet.BytesToLetters([]byte("GG")), alphabet.DNA)
+	d.SetOffset(8)
+	for _, m := range []seq.Sequence{a, b, d} {
+		c.Check(con.Insert(m), check.Equals, nil)
+	}
+	c.Check(con.InsertGap(Gap{Start: 6, End: 7}), check.Equals, nil)
+
+	cov, err := con.Pileup(0, 12)
+	c.Check(err, check.Equals, nil)
+	c.Check(cov, check.DeepEquals, []Coverage{
+		{Start: 0, End: 3, Members: []seq.Sequence{a}},
+		{Start: 3, End: 6, Members: []seq.Sequence{a, b}},
+		{Start: 6, End: 8},
+		{Start: 8, End: 10, Members: []seq.Sequence{d}},
+		{Start: 10, End: 12},
+	})
+	c.Check(cov[1].Depth(), check.Equals, 2)
+	c.Check(con.DepthHistogram(), check.DeepEquals, []int{4, 5, 3})
+	c.Check(con.ZeroCoverage(), check.DeepEquals, []Interval{{6, 8}, {10, 12}})
+
+	con.RevComp()
+	c.Check(con.ZeroCoverage(), check.DeepEquals, []Interval{{0, 2}, {4, 6}})
+	var buf bytes.Buffer
+	w := NewBedGraphWriter(&buf)
+	w.Name = "depth"
+	c.Check(w.Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, `track type=bedGraph name="depth"`+"\n"+
+		"test\t0\t2\t0\n"+
+		"test\t2\t4\t1\n"+
+		"test\t4\t6\t0\n"+
+		"test\t6\t9\t2\n"+
+		"test\t9\t12\t1\n")
+
+	c.Check(con.SetOffset(-5), check.Equals, nil)
+	buf.Reset()
+	c.Check(w.Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, "test\t0\t2\t0\n"+
+		"test\t2\t4\t1\n"+
+		"test\t4\t6\t0\n"+
+		"test\t6\t9\t2\n"+
+		"test\t9\t12\t1\n")
+}
+
+func (s *S) TestQuality(c *check.C) {
+	con, err := New("test", 6, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	con.Desc = "quality"
+	con.SetGroundQuality(0)
+	c.Check(con.GroundQuality(), check.Equals, alphabet.Qphred(0))
+	ql := alphabet.QLetters{{L: 'A', Q: 10}, {L: 'C', Q: 20}, {L: 'G', Q: 30}, {L: 'T', Q: 40}}
+	c.Check(con.Insert(linear.NewQSeq("a", ql, alphabet.DNA, alphabet.Sanger)), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 4, End: 5}), check.Equals, nil)
+
+	c.Check(fmt.Sprintf("%q", con), check.Equals, "@test quality\nACGTnn\n+\n+5?I!!")
+	c.Check(fmt.Sprintf("%.5q", con), check.Equals, "@test quality\nACGTn\n+\n+5?I!...")
+	c.Check(con.Slice(), check.DeepEquals, append(ql[:4:4], alphabet.QLetter{L: 'n'}, alphabet.QLetter{L: 'n'}))
+
+	con.RevComp()
+	c.Check(fmt.Sprintf("%q", con), check.Equals, "@test quality\nnnACGT\n+\n!!I?5+")
+	c.Check(con.At(2), check.Equals, alphabet.QLetter{L: 'A', Q: 40})
+
+	sub, err := con.Subcontig(1, 4)
+	c.Check(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%q", sub), check.Equals, "@test quality\nnAC\n+\n!I?")
+
+	pl := linear.NewSeq("b", alphabet.BytesToLetters([]byte("AC")), alphabet.DNA)
+	lin, err := New("linear", 2, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(lin.Insert(pl), check.Equals, nil)
+	_, ok := lin.Slice().(alphabet.Letters)
+	c.Check(ok, check.Equals, true)
+}
+
+func (s *S) TestMask(c *check.C) {
+	con, err := New("test", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(con.Insert(linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTACGTAC")), alphabet.DNA)), check.Equals, nil)
+
+	bed, err := ReadBEDIntervals(strings.NewReader("track name=repeats\ntest\t2\t5\trepeat\nother\t0\t1\n"))
+	c.Assert(err, check.Equals, nil)
+	c.Check(bed, check.DeepEquals, map[string][]Interval{"test": {{2, 5}}, "other": {{0, 1}}})
+	rm, err := ReadRepeatMasker(strings.NewReader(`   SW  perc perc perc  query      position in query           matching       repeat              position in  repeat
+score  div. del. ins.  sequence    begin     end    (left)    repeat         class/family         begin  end (left)   ID
+
+  463   1.3  0.6  1.7  test           8       9     (1) +  AluY           SINE/Alu               1  311   (0)   1
+`))
+	c.Assert(err, check.Equals, nil)
+	c.Check(rm, check.DeepEquals, map[string][]Interval{"test": {{7, 9}}})
+
+	c.Check(con.MaskIntervals(append(bed["test"], rm["test"]...)), check.Equals, nil)
+	c.Check(con.MaskIntervals([]Interval{{8, 11}}), check.ErrorMatches, "contig: mask out of range")
+	c.Check(con.Masked(), check.DeepEquals, []Interval{{2, 5}, {7, 9}})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACGTACGTAC")
+	con.SetMasking(SoftMasking)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACgtaCGtaC")
+	con.SetMasking(HardMasking)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACNNNCGNNC")
+	c.Check(con.At(3).L, check.Equals, alphabet.Letter('N'))
+
+	con.SetMasking(SoftMasking)
+	con.RevComp()
+	c.Check(con.Masked(), check.DeepEquals, []Interval{{1, 3}, {5, 8}})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "GtaCGtacGT")
+	c.Check(con.At(5).L, check.Equals, alphabet.Letter('t'))
+
+	sub, err := con.Subcontig(4, 9)
+	c.Assert(err, check.Equals, nil)
+	c.Check(sub.Masked(), check.DeepEquals, []Interval{{1, 4}})
+	c.Check(fmt.Sprintf("%-s", sub), check.Equals, "GtacG")
+
+	c.Check(con.Materialize(), check.Equals, nil)
+	c.Check(con.Masked(), check.DeepEquals, []Interval{{1, 3}, {5, 8}})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "GtaCGtacGT")
+	con.ClearMasks()
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "GTACGTACGT")
+
+	circ, err := NewCircular("circ", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(circ.Insert(linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTACGTAC")), alphabet.DNA)), check.Equals, nil)
+	c.Check(circ.Mask(8, 12), check.Equals, nil)
+	c.Check(circ.Masked(), check.DeepEquals, []Interval{{0, 2}, {8, 10}})
+	c.Check(circ.Rotate(5), check.Equals, nil)
+	c.Check(circ.Masked(), check.DeepEquals, []Interval{{3, 7}})
+}
+
+func (s *S) TestPacked(c *check.C) {
+	p := NewPacked("p", alphabet.BytesToLetters([]byte("ACGTNNacgtRY")), alphabet.DNA)
+	c.Check(p.Len(), check.Equals, 12)
+	c.Check(p.Slice(), check.DeepEquals, alphabet.Letters(alphabet.BytesToLetters([]byte("ACGTNNacgtNN"))))
+	c.Check(p.Set(1, alphabet.QLetter{L: 'g'}), check.Equals, nil)
+	c.Check(p.Set(4, alphabet.QLetter{L: 'T'}), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", p), check.Equals, "AgGTTNacgtNN")
+	c.Check(p.Set(12, alphabet.QLetter{L: 'A'}), check.ErrorMatches, "contig: position out of range")
+	c.Check(p.Set(-1, alphabet.QLetter{L: 'A'}), check.ErrorMatches, "contig: position out of range")
+	pc := p.Clone().(*Packed)
+	pc.RevComp()
+	c.Check(fmt.Sprintf("%-s", pc), check.Equals, "NNacgtNAACcT")
+	c.Check(fmt.Sprintf("%-s", p), check.Equals, "AgGTTNacgtNN")
+
+	c.Check(Packable(linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTn")), alphabet.DNA)), check.Equals, true)
+	c.Check(Packable(linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTR")), alphabet.DNA)), check.Equals, false)
+
+	con, err := New("test", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTacgt")), alphabet.DNA)
+	a.SetOffset(2)
+	c.Check(con.Insert(a), check.Equals, nil)
+	con.Pack()
+	m, ok := con.Lookup("a")
+	c.Assert(ok, check.Equals, true)
+	_, ok = m.Seq.(*Packed)
+	c.Check(ok, check.Equals, true)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "nnACGTacgtnn")
+
+	var buf bytes.Buffer
+	fw := NewFASTAWriter(&buf, 5)
+	c.Check(fw.Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, ">test\nnnACG\nTacgt\nnn\n")
+
+	chr2, err := New("chr2", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(chr2.Insert(linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGCC")), alphabet.DNA)), check.Equals, nil)
+	buf.Reset()
+	c.Check(WriteTwoBit(&buf, con, chr2), check.Equals, nil)
+	b := buf.Bytes()
+	c.Check(b[:4], check.DeepEquals, []byte{0x43, 0x27, 0x41, 0x1a})
+	c.Check(b[len(b)-1], check.Equals, byte(0xf5))
+
+	cons, err := ReadTwoBit(bytes.NewReader(b))
+	c.Assert(err, check.Equals, nil)
+	c.Assert(len(cons), check.Equals, 2)
+	c.Check(cons[0].ID, check.Equals, "test")
+	c.Check(fmt.Sprintf("%-s", cons[0]), check.Equals, "nnACGTacgtnn")
+	c.Check(cons[1].ID, check.Equals, "chr2")
+	c.Check(fmt.Sprintf("%-s", cons[1]), check.Equals, "GGCC")
+	_, err = ReadTwoBit(strings.NewReader("not a 2bit file"))
+	c.Check(err, check.ErrorMatches, "contig: 2bit: invalid signature")
+
+	// Corrupt counts and sizes fail without allocating for them.
+	buf.Reset()
+	c.Check(WriteTwoBit(&buf, chr2), check.Equals, nil)
+	for _, t := range []struct {
+		at   int
+		val  uint32
+		want string
+	}{
+		{at: 8, val: 0xffffffff, want: "unexpected EOF"},                        // Sequence count.
+		{at: 25, val: 0xffffffff, want: `contig: 2bit: "chr2": unexpected EOF`}, // DNA size.
+		{at: 29, val: 0xffffffff, want: `contig: 2bit: "chr2": unexpected EOF`}, // N block count.
+	} {
+		b := append([]byte(nil), buf.Bytes()...)
+		binary.LittleEndian.PutUint32(b[t.at:], t.val)
+		_, err = ReadTwoBit(bytes.NewReader(b))
+		c.Check(err, check.ErrorMatches, t.want)
+	}
+	b = append([]byte(nil), buf.Bytes()[:29]...)
+	for _, v := range []uint32{1, 2, 3, 0, 0} {
+		// One N block at [2,5) in a sequence of length 4.
+		var w [4]byte
+		binary.LittleEndian.PutUint32(w[:], v)
+		b = append(b, w[:]...)
+	}
+	b = append(b, buf.Bytes()[len(buf.Bytes())-1])
+	_, err = ReadTwoBit(bytes.NewReader(b))
+	c.Check(err, check.ErrorMatches, `contig: 2bit: "chr2": block out of range`)
+}
+
+func (s *S) TestDiagram(c *check.C) {
+	con, err := New("scaffold", 60, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(con.Insert(linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTACGTACGTACGTAC")), alphabet.DNA)), check.Equals, nil)
+	part, err := New("part", 12, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(part.Insert(linear.NewSeq("b", alphabet.BytesToLetters([]byte("GTACGTACGTAC")), alphabet.DNA)), check.Equals, nil)
+	c.Check(con.Merge(part, 14, seq.Minus), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 40, End: 52}), check.Equals, nil)
+
+	var buf bytes.Buffer
+	c.Check(con.WriteDiagram(&buf, 2), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, `scaffold [0,60) 2 positions/column
+        0         20        40
+        |---------|---------|---------
+a       ========>
+b              <=====
+gaps                        ======
+overlap        ==
+`)
+	c.Check(con.WriteDiagram(&buf, 0), check.ErrorMatches, "contig: invalid scale")
+
+	con.RevComp()
+	buf.Reset()
+	c.Check(con.WriteDiagram(&buf, 4), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, `scaffold [0,60) 4 positions/column
+        0         40
+        |---------|----
+b               ===>
+a                 <====
+gaps      ===
+overlap           ==
+`)
+
+	buf.Reset()
+	c.Check(con.WriteSVG(&buf, 0.5), check.Equals, nil)
+	svg := buf.String()
+	c.Check(strings.HasPrefix(svg, `<svg xmlns="http://www.w3.org/2000/svg" width="224" height="96"`), check.Equals, true)
+	c.Check(strings.Contains(svg, `<title>a - [42,60)</title>`), check.Equals, true)
+	c.Check(strings.Contains(svg, `<title>gaps [8,20)</title>`), check.Equals, true)
+	c.Check(strings.HasSuffix(svg, "</svg>\n"), check.Equals, true)
+}
+
+func (s *S) TestDiff(c *check.C) {
+	member := func(id string, n, offset int) seq.Sequence {
+		m := linear.NewSeq(id, alphabet.BytesToLetters(bytes.Repeat([]byte("A"), n)), alphabet.DNA)
+		m.SetOffset(offset)
+		return m
+	}
+	old, err := New("v1", 40, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	for _, m := range []seq.Sequence{member("a", 10, 0), member("b", 10, 15), member("c", 10, 30)} {
+		c.Check(old.Insert(m), check.Equals, nil)
+	}
+	c.Check(old.InsertGap(Gap{Start: 10, End: 15}), check.Equals, nil)
+	c.Check(old.InsertGap(Gap{Start: 25, End: 30}), check.Equals, nil)
+
+	upd, err := New("v2", 40, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(upd.Insert(member("a", 10, 0)), check.Equals, nil)
+	part, err := New("part", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(part.Insert(member("b", 10, 0)), check.Equals, nil)
+	c.Check(upd.Merge(part, 12, seq.Minus), check.Equals, nil)
+	c.Check(upd.Insert(member("d", 10, 25)), check.Equals, nil)
+	c.Check(upd.InsertGap(Gap{Start: 10, End: 12}), check.Equals, nil)
+	c.Check(upd.InsertGap(Gap{Start: 22, End: 25}), check.Equals, nil)
+
+	c.Check(Diff(old, upd), check.DeepEquals, []Edit{
+		{Kind: MemberRemoved, ID: "c", OldStrand: seq.Plus, Old: Interval{30, 40}},
+		{Kind: MemberAdded, ID: "d", NewStrand: seq.Plus, New: Interval{25, 35}},
+		{Kind: MemberMoved, ID: "b", OldStrand: seq.Plus, NewStrand: seq.Minus, Old: Interval{15, 25}, New: Interval{12, 22}},
+		{Kind: MemberReoriented, ID: "b", OldStrand: seq.Plus, NewStrand: seq.Minus, Old: Interval{15, 25}, New: Interval{12, 22}},
+		{Kind: GapRemoved, Left: "b", Right: "c", Old: Interval{25, 30}},
+		{Kind: GapAdded, Left: "b", Right: "d", New: Interval{22, 25}},
+		{Kind: GapAdded, Left: "d", New: Interval{35, 40}},
+		{Kind: GapResized, Left: "a", Right: "b", Old: Interval{10, 15}, New: Interval{10, 12}},
+	})
+	c.Check(Diff(old, old), check.HasLen, 0)
+
+	var buf bytes.Buffer
+	c.Check(WriteDiff(&buf, old, upd), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, `--- v1 [0,40)
++++ v2 [0,40)
+- member c [30,40) +
++ member d [25,35) +
+~ member b moved [15,25) -> [12,22)
+~ member b reoriented + -> -
+- gap [25,30) between b and c
++ gap [22,25) between b and d
++ gap [35,40) between d and end
+~ gap resized 5 -> 2 [10,15) -> [10,12) between a and b
+`)
+
+	// Ground state spacing is diffed as gap.
+	spaced := func(id string, n, at int) *Contig {
+		con, err := New(id, n, alphabet.DNA)
+		c.Assert(err, check.Equals, nil)
+		c.Check(con.Insert(member("a", 10, 0)), check.Equals, nil)
+		c.Check(con.Insert(member("b", 10, at)), check.Equals, nil)
+		return con
+	}
+	c.Check(Diff(spaced("v1", 25, 15), spaced("v2", 22, 12)), check.DeepEquals, []Edit{
+		{Kind: MemberMoved, ID: "b", OldStrand: seq.Plus, NewStrand: seq.Plus, Old: Interval{15, 25}, New: Interval{12, 22}},
+		{Kind: GapResized, Left: "a", Right: "b", Old: Interval{10, 15}, New: Interval{10, 12}},
+	})
+}
+
+func (s *S) TestEditor(c *check.C) {
+	con, err := New("test", 20, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("AAAAACCCCC")), alphabet.DNA)
+	c.Check(con.Insert(a), check.Equals, nil)
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGGG")), alphabet.DNA)
+	b.SetOffset(14)
+	c.Check(con.Insert(b), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 10, End: 14}), check.Equals, nil)
+
+	ed := NewEditor(con)
+	c.Check(ed.Substitute(2, alphabet.BytesToLetters([]byte("TT"))), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AATTACCCCCnnnnGGGGnn")
+	c.Check(fmt.Sprintf("%-s", a), check.Equals, "AAAAACCCCC")
+	c.Check(ed.Substitute(11, alphabet.BytesToLetters([]byte("AC"))), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AATTACCCCCnACnGGGGnn")
+	c.Check(con.Gaps(), check.HasLen, 2)
+	c.Check(ed.Insert(5, alphabet.BytesToLetters([]byte("GGG"))), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AATTAGGGCCCCCnACnGGGGnn")
+	m, ok := con.Lookup("a")
+	c.Assert(ok, check.Equals, true)
+	c.Check(m.End, check.Equals, 13)
+	c.Check(ed.Delete(0, 2), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTAGGGCCCCCnACnGGGGnn")
+	c.Check(ed.Insert(21, alphabet.BytesToLetters([]byte("TT"))), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTAGGGCCCCCnACnGGGGnnTT")
+	c.Check(ed.Delete(0, 23), check.ErrorMatches, "contig: cannot delete entire contig")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTAGGGCCCCCnACnGGGGnnTT")
+
+	c.Check(ed.Undo(), check.Equals, nil)
+	c.Check(ed.Undo(), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AATTAGGGCCCCCnACnGGGGnn")
+	c.Check(ed.Redo(), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "TTAGGGCCCCCnACnGGGGnn")
+	c.Check(ed.Substitute(0, alphabet.BytesToLetters([]byte("C"))), check.Equals, nil)
+	c.Check(ed.Redo(), check.ErrorMatches, "contig: nothing to redo")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "CTAGGGCCCCCnACnGGGGnn")
+
+	log := ed.Log()
+	c.Assert(log, check.HasLen, 5)
+	c.Check(log[0], check.DeepEquals, Patch{Op: Substitution, Pos: 2, Letters: alphabet.BytesToLetters([]byte("TT")), Member: "a", MemberPos: 2, Strand: seq.Plus})
+	c.Check(log[3], check.DeepEquals, Patch{Op: Deletion, Pos: 0, Letters: alphabet.BytesToLetters([]byte("AA")), Member: "a", MemberPos: 0, Strand: seq.Plus})
+	var buf bytes.Buffer
+	c.Check(WritePatches(&buf, log[:2]), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, "S\t2\tTT\ta\t2\t+\nS\t11\tAC\t.\t0\t.\n")
+	patches, err := ReadPatches(&buf)
+	c.Check(err, check.Equals, nil)
+	c.Check(patches, check.DeepEquals, log[:2])
+
+	for _, u := range []int{5, 4, 3, 2, 1} {
+		c.Check(ed.Undo(), check.Equals, nil, check.Commentf("undo %d", u))
+	}
+	c.Check(ed.Undo(), check.ErrorMatches, "contig: nothing to undo")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "AAAAACCCCCnnnnGGGGnn")
+	c.Check(con.Members(), check.HasLen, 2)
+
+	// Patches are located by their member after re-assembly.
+	rev, err := New("rev", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	part, err := New("part", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(part.Insert(a), check.Equals, nil)
+	c.Check(rev.Merge(part, 0, seq.Minus), check.Equals, nil)
+	c.Check(NewEditor(rev).Apply(log[0]), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rev), check.Equals, "GGGGGTAATT")
+
+	rev.RevComp()
+	ed = NewEditor(rev)
+	c.Check(ed.Insert(4, alphabet.BytesToLetters([]byte("GT"))), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rev), check.Equals, "AATTGTACCCCC")
+	c.Check(ed.Delete(0, 2), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rev), check.Equals, "TTGTACCCCC")
+	c.Check(ed.Undo(), check.Equals, nil)
+	c.Check(ed.Undo(), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rev), check.Equals, "AATTACCCCC")
+}
+
+func (s *S) TestScaffold(c *check.C) {
+	seqs := make(map[string]seq.Sequence)
+	for _, q := range []struct{ id, s string }{
+		{"q1", "ACGTACGTAC"},
+		{"q2", "GGGGCCCCAA"},
+		{"q3", "GTACG"},
+		{"q4", "TTTTTAAAAA"},
+		{"q5", "CCCCC"},
+	} {
+		seqs[q.id] = linear.NewSeq(q.id, alphabet.BytesToLetters([]byte(q.s)), alphabet.DNA)
+	}
+	alns, err := ReadPAF(strings.NewReader(`q1	10	0	10	+	ref	100	5	15	10	10	60
+q2	10	0	10	-	ref	100	30	40	10	10	60
+q3	5	0	5	+	ref	100	8	13	5	5	60
+q4	10	2	10	+	ref	100	40	48	8	8	60
+q5	5	0	5	+	ref	100	70	75	5	5	0
+`))
+	c.Assert(err, check.Equals, nil)
+	c.Check(alns, check.HasLen, 5)
+	c.Check(alns[3], check.DeepEquals, Alignment{
+		Query: "q4", QueryLen: 10, QueryStart: 2, QueryEnd: 10, Strand: seq.Plus,
+		Target: "ref", TargetLen: 100, TargetStart: 40, TargetEnd: 48,
+		Matches: 8, MapQ: 60,
+	})
+
+	sc := Scaffolder{MinMapQ: 10, MinGap: 5, UnknownGap: 3}
+	cons, unplaced, err := sc.Scaffold(alns, seqs)
+	c.Assert(err, check.Equals, nil)
+	c.Assert(cons, check.HasLen, 1)
+	c.Check(unplaced, check.DeepEquals, []string{"q3", "q5"})
+	con := cons[0]
+	c.Check(con.ID, check.Equals, "ref")
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACGTACGTACnnnnnnnnnnnnnnnTTGGGGCCCCnnnTTTTTAAAAA")
+	var buf bytes.Buffer
+	c.Check(NewAGPWriter(&buf).Write(con), check.Equals, nil)
+	c.Check(buf.String(), check.Equals, `##agp-version	2.1
+ref	1	10	1	W	q1	1	10	+
+ref	11	25	2	N	15	scaffold	yes	align_genus
+ref	26	35	3	W	q2	1	10	-
+ref	36	38	4	U	3	scaffold	yes	align_genus
+ref	39	48	5	W	q4	1	10	+
+`)
+
+	alns, err = ReadSAM(strings.NewReader(`@HD	VN:1.6
+@SQ	SN:ref	LN:100
+q2	16	ref	31	60	2S8M	*	0	0	CCCCAAGG	*	NM:i:1
+q3	4	*	0	0	*	*	0	0	GTACG	*
+q4	0	ref	41	60	2H3M1I4M	*	0	0	TTTAAAAA	*
+`))
+	c.Assert(err, check.Equals, nil)
+	c.Assert(alns, check.HasLen, 2)
+	c.Check(alns[0], check.DeepEquals, Alignment{
+		Query: "q2", QueryLen: 10, QueryStart: 0, QueryEnd: 8, Strand: seq.Minus,
+		Target: "ref", TargetLen: 100, TargetStart: 30, TargetEnd: 38,
+		Matches: 7, MapQ: 60,
+	})
+	c.Check(alns[1], check.DeepEquals, Alignment{
+		Query: "q4", QueryLen: 10, QueryStart: 2, QueryEnd: 10, Strand: seq.Plus,
+		Target: "ref", TargetLen: 100, TargetStart: 40, TargetEnd: 47,
+		Matches: 7, MapQ: 60,
+	})
+	_, err = ReadSAM(strings.NewReader("q1\t0\tref\t1\t60\t4Z\t*\t0\t0\t*\t*\n"))
+	c.Check(err, check.ErrorMatches, `contig: sam line 1: invalid cigar operation 'Z'`)
+
+	// Queries abutting on the reference are placed without a gap.
+	alns, err = ReadPAF(strings.NewReader(`q1	10	0	10	+	ref	100	5	15	10	10	60
+q5	5	0	5	+	ref	100	15	20	5	5	60
+`))
+	c.Assert(err, check.Equals, nil)
+	cons, _, err = sc.Scaffold(alns, seqs)
+	c.Assert(err, check.Equals, nil)
+	c.Assert(cons, check.HasLen, 1)
+	c.Check(fmt.Sprintf("%-s", cons[0]), check.Equals, "ACGTACGTACCCCCC")
+	c.Check(cons[0].Gaps(), check.HasLen, 0)
+
+	// Equally good placements are chosen independently of map order.
+	alns, err = ReadPAF(strings.NewReader(`q1	10	0	10	+	refB	100	5	15	10	10	60
+q1	10	0	10	-	refA	100	5	15	10	10	60
+q1	10	0	10	+	refA	100	5	15	10	10	60
+`))
+	c.Assert(err, check.Equals, nil)
+	for i := 0; i < 20; i++ {
+		cons, _, err = sc.Scaffold(alns, seqs)
+		c.Assert(err, check.Equals, nil)
+		c.Assert(cons, check.HasLen, 1)
+		c.Check(cons[0].ID, check.Equals, "refA")
+		m, ok := cons[0].Lookup("q1")
+		c.Assert(ok, check.Equals, true)
+		c.Check(m.Strand, check.Equals, seq.Minus)
+	}
+}
+
+func (s *S) TestLayout(c *check.C) {
+	con, err := New("scf", 20, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	con.Desc = "test scaffold"
+	con.Relaxed(true)
+	con.SetPolicy(FirstWins)
+	con.SetMasking(SoftMasking)
+	con.SetGroundQuality(5)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTACGT")), alphabet.DNA)
+	a.Desc = "member a"
+	c.Check(con.Insert(a), check.Equals, nil)
+	part, err := New("part", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	b := linear.NewQSeq("b", []alphabet.QLetter{{L: 'G', Q: 10}, {L: 'G', Q: 20}, {L: 'C', Q: 30}, {L: 'A', Q: 40}}, alphabet.DNA, alphabet.Sanger)
+	c.Check(part.Insert(b), check.Equals, nil)
+	c.Check(con.Merge(part, 12, seq.Minus), check.Equals, nil)
+	p := NewPacked("p", alphabet.BytesToLetters([]byte("TTTT")), alphabet.DNA)
+	p.SetOffset(18)
+	c.Check(con.Insert(p), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: 8, End: 12, Type: ScaffoldGap, Linkage: true, Evidence: []Evidence{PairedEnds, Map}}), check.Equals, nil)
+	c.Check(con.Mask(2, 4), check.Equals, nil)
+	con.Strict(true)
+	con.SetOffset(-2)
+	con.Strand = seq.Minus
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, "ACgtACGTnnnnTGCCnnTTTT")
+
+	for _, write := range []func(io.Writer, SeqStorage, ...*Contig) error{WriteLayout, WriteLayoutJSON} {
+		var buf bytes.Buffer
+		c.Assert(write(&buf, InlineSeqs, con), check.Equals, nil)
+		got, err := ReadLayout(&buf, nil)
+		c.Assert(err, check.Equals, nil)
+		c.Assert(got, check.HasLen, 1)
+		r := got[0]
+		c.Check(r.ID, check.Equals, "scf")
+		c.Check(r.Desc, check.Equals, "test scaffold")
+		c.Check(r.Strand, check.Equals, seq.Minus)
+		c.Check(r.Start(), check.Equals, -2)
+		c.Check(r.IsRelaxed(), check.Equals, true)
+		c.Check(r.IsStrict(), check.Equals, true)
+		c.Check(r.Policy(), check.Equals, FirstWins)
+		c.Check(r.Masking(), check.Equals, SoftMasking)
+		c.Check(r.GroundQuality(), check.Equals, alphabet.Qphred(5))
+		c.Check(r.Joiner(), check.Equals, con.Joiner())
+		c.Check(fmt.Sprintf("%-s", r), check.Equals, fmt.Sprintf("%-s", con))
+		c.Check(fmt.Sprintf("%-q", r), check.Equals, fmt.Sprintf("%-q", con))
+		c.Check(r.Gaps(), check.DeepEquals, con.Gaps())
+		c.Check(r.Masked(), check.DeepEquals, con.Masked())
+		rm, cm := r.Members(), con.Members()
+		c.Assert(rm, check.HasLen, len(cm))
+		for i := range cm {
+			c.Check(rm[i].Seq.Name(), check.Equals, cm[i].Seq.Name())
+			c.Check(rm[i].Seq.Start(), check.Equals, cm[i].Seq.Start())
+			c.Check(rm[i].Seq.Description(), check.Equals, cm[i].Seq.Description())
+			c.Check([]int{rm[i].Start, rm[i].End}, check.DeepEquals, []int{cm[i].Start, cm[i].End})
+			c.Check(rm[i].Strand, check.Equals, cm[i].Strand)
+		}
+		_, ok := rm[2].Seq.(*Packed)
+		c.Check(ok, check.Equals, true)
+	}
+
+	// Features and unsupported alphabets are not encoded.
+	ann := con.Clone().(*Contig)
+	c.Check(ann.Annotate(testFeature{name: "f", start: 0, end: 2}), check.Equals, nil)
+	for _, write := range []func(io.Writer, SeqStorage, ...*Contig) error{WriteLayout, WriteLayoutJSON} {
+		c.Check(write(&bytes.Buffer{}, InlineSeqs, ann), check.ErrorMatches, `contig: layout: cannot encode features of "scf"`)
+	}
+	red, err := New("red", 4, alphabet.DNAredundant)
+	c.Assert(err, check.Equals, nil)
+	c.Check(WriteLayout(&bytes.Buffer{}, InlineSeqs, red), check.ErrorMatches, `contig: layout: unsupported alphabet for "red"`)
+
+	// Circular layouts are written in their current orientation.
+	circ, err := NewCircular("circ", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	a = linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGTA")), alphabet.DNA)
+	a.SetOffset(7)
+	c.Check(circ.Insert(a), check.Equals, nil)
+	circ.RevComp()
+	var buf bytes.Buffer
+	c.Assert(WriteLayoutJSON(&buf, InlineSeqs, circ), check.Equals, nil)
+	got, err := ReadLayout(&buf, nil)
+	c.Assert(err, check.Equals, nil)
+	c.Check(got[0].IsCircular(), check.Equals, true)
+	c.Check(fmt.Sprintf("%-s", got[0]), check.Equals, fmt.Sprintf("%-s", circ))
+
+	// Referenced members are read from their component sequences.
+	comps, err := ReadComponents(strings.NewReader(agpComponents), alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	agp, err := NewAGPReader(strings.NewReader(agpLayout), comps, alphabet.DNA).Read()
+	c.Assert(err, check.Equals, nil)
+	buf.Reset()
+	c.Assert(WriteLayout(&buf, ReferencedSeqs, agp), check.Equals, nil)
+	c.Check(bytes.Contains(buf.Bytes(), []byte("GGAC")), check.Equals, false)
+	layout := buf.String()
+	got, err = ReadLayout(strings.NewReader(layout), comps)
+	c.Assert(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", got[0]), check.Equals, fmt.Sprintf("%-s", agp))
+	var want, have bytes.Buffer
+	c.Check(NewAGPWriter(&want).Write(agp), check.Equals, nil)
+	c.Check(NewAGPWriter(&have).Write(got[0]), check.Equals, nil)
+	c.Check(have.String(), check.Equals, want.String())
+	_, err = ReadLayout(strings.NewReader(layout), nil)
+	c.Check(err, check.ErrorMatches, `contig: layout: "scf1": no sequence for "ctg1"`)
+
+	_, err = ReadLayout(strings.NewReader("BGCL\x03\x00\x00\x00"), nil)
+	c.Check(err, check.ErrorMatches, "contig: layout: unsupported version 3")
+
+	// Version 1 layouts do not hold the Contig's strand.
+	buf.Reset()
+	c.Assert(WriteLayoutJSON(&buf, InlineSeqs, con), check.Equals, nil)
+	v1 := strings.Replace(buf.String(), `"version":2`, `"version":1`, 1)
+	c.Assert(v1, check.Not(check.Equals), buf.String())
+	got, err = ReadLayout(strings.NewReader(v1), nil)
+	c.Assert(err, check.Equals, nil)
+	c.Check(got[0].Strand, check.Equals, seq.None)
+	c.Check(fmt.Sprintf("%-s", got[0]), check.Equals, fmt.Sprintf("%-s", con))
+	_, err = ReadLayout(strings.NewReader(`{"format":"other","version":1}`), nil)
+	c.Check(err, check.ErrorMatches, "contig: layout: invalid format")
+}
+
+func (s *S) TestBuilder(c *check.C) {
+	con, err := New("scf", 40, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	b := NewBuilder(con)
+
+	var (
+		wg   sync.WaitGroup
+		errs = make([]error, 8)
+		lens = make([]int, 8)
+	)
+	for i := range errs {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			m := linear.NewSeq(fmt.Sprint("m", i), alphabet.BytesToLetters([]byte("ACG")), alphabet.DNA)
+			m.SetOffset(5 * i)
+			errs[i] = b.Insert(m)
+			if errs[i] == nil {
+				errs[i] = b.InsertGap(Gap{Start: 5*i + 3, End: 5*i + 5})
+			}
+			snap := b.Snapshot()
+			lens[i] = len(fmt.Sprintf("%-s", snap))
+		}(i)
+	}
+	wg.Wait()
+	for i := range errs {
+		c.Check(errs[i], check.Equals, nil)
+		c.Check(lens[i], check.Equals, 40)
+	}
+
+	snap := b.Snapshot()
+	c.Check(b.Snapshot(), check.Equals, snap)
+	c.Check(snap.IsSnapshot(), check.Equals, true)
+	c.Check(snap.Members(), check.HasLen, 8)
+	c.Check(snap.Gaps(), check.HasLen, 8)
+	want := strings.Repeat("ACGnn", 8)
+	var (
+		rg   sync.WaitGroup
+		read = make([]string, 4)
+	)
+	for i := range read {
+		rg.Add(1)
+		go func(i int) {
+			defer rg.Done()
+			read[i] = fmt.Sprintf("%-s", snap)
+		}(i)
+	}
+	rg.Wait()
+	for _, r := range read {
+		c.Check(r, check.Equals, want)
+	}
+
+	_, err = b.Remove("m0")
+	c.Check(err, check.Equals, nil)
+	c.Check(b.Snapshot(), check.Not(check.Equals), snap)
+	c.Check(fmt.Sprintf("%-s", b.Snapshot()), check.Equals, "nnnnn"+strings.Repeat("ACGnn", 7))
+	c.Check(fmt.Sprintf("%-s", snap), check.Equals, want)
+
+	c.Check(snap.Insert(linear.NewSeq("x", alphabet.BytesToLetters([]byte("A")), alphabet.DNA)), check.ErrorMatches, "contig: snapshot is read-only")
+	c.Check(snap.SetOffset(2), check.ErrorMatches, "contig: snapshot is read-only")
+	c.Check(snap.Set(0, alphabet.QLetter{L: 'T'}), check.ErrorMatches, "contig: snapshot is read-only")
+	c.Check(func() { snap.RevComp() }, check.PanicMatches, "contig: snapshot is read-only")
+	c.Check(NewEditor(snap).Substitute(0, alphabet.BytesToLetters([]byte("T"))), check.ErrorMatches, "contig: snapshot is read-only")
+	clone := snap.Clone().(*Contig)
+	c.Check(clone.IsSnapshot(), check.Equals, false)
+	c.Check(clone.SetOffset(2), check.Equals, nil)
+	c.Check(NewBuilder(snap).Snapshot().Start(), check.Equals, 0)
+
+	// Merging a snapshot does not share its members.
+	dst, err := New("dst", 40, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	c.Check(dst.Merge(snap, 0, seq.Plus), check.Equals, nil)
+	c.Check(dst.Set(0, alphabet.QLetter{L: 'T'}), check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", dst)[:3], check.Equals, "TCG")
+	c.Check(fmt.Sprintf("%-s", snap), check.Equals, want)
+	m, ok := snap.Lookup("m0")
+	c.Assert(ok, check.Equals, true)
+	c.Check(fmt.Sprintf("%-s", m.Seq), check.Equals, "ACG")
+}
+
+func (s *S) TestNormalize(c *check.C) {
+	con, err := New("scf", 10, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	con.Relaxed(true)
+	a := linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)
+	a.SetOffset(-6)
+	c.Check(con.Insert(a), check.Equals, nil)
+	b := linear.NewSeq("b", alphabet.BytesToLetters([]byte("GGCC")), alphabet.DNA)
+	b.SetOffset(4)
+	c.Check(con.Insert(b), check.Equals, nil)
+	c.Check(con.InsertGap(Gap{Start: -2, End: 1}), check.Equals, nil)
+	c.Check(con.Mask(5, 7), check.Equals, nil)
+	c.Check(con.Annotate(testFeature{name: "f", start: -5, end: -3}), check.Equals, nil)
+	c.Check(con.Start(), check.Equals, -6)
+	letters := fmt.Sprintf("%-s", con)
+
+	shift, err := con.Normalize()
+	c.Check(err, check.Equals, nil)
+	c.Check(shift, check.Equals, 6)
+	c.Check([]int{con.Start(), con.End()}, check.DeepEquals, []int{0, 16})
+	c.Check(fmt.Sprintf("%-s", con), check.Equals, letters)
+	m, _ := con.Lookup("a")
+	c.Check([]int{m.Start, m.End}, check.DeepEquals, []int{0, 4})
+	m, _ = con.Lookup("b")
+	c.Check([]int{m.Start, m.End}, check.DeepEquals, []int{10, 14})
+	c.Check(a.Start(), check.Equals, -6)
+	gaps := con.Gaps()
+	c.Assert(gaps, check.HasLen, 1)
+	c.Check([]int{gaps[0].Start, gaps[0].End}, check.DeepEquals, []int{4, 7})
+	c.Check(con.Masked(), check.DeepEquals, []Interval{{11, 13}})
+	f := con.Features()
+	c.Assert(f, check.HasLen, 1)
+	c.Check([]int{f[0].Start, f[0].End}, check.DeepEquals, []int{1, 3})
+	c.Check(con.Insert(linear.NewSeq("c", alphabet.BytesToLetters([]byte("T")), alphabet.DNA)), check.Equals, nil)
+	c.Check(con.At(0).L, check.Equals, alphabet.Letter('T'))
+
+	shift, err = con.Normalize()
+	c.Check(err, check.Equals, nil)
+	c.Check(shift, check.Equals, 0)
+
+	rc, err := New("rc", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	rc.Relaxed(true)
+	a = linear.NewSeq("a", alphabet.BytesToLetters([]byte("AACG")), alphabet.DNA)
+	a.SetOffset(-2)
+	c.Check(rc.Insert(a), check.Equals, nil)
+	rc.RevComp()
+	c.Check(rc.Start(), check.Equals, -2)
+	_, err = rc.Normalize()
+	c.Check(err, check.Equals, nil)
+	c.Check(fmt.Sprintf("%-s", rc), check.Equals, "nnCGTT")
+	m, _ = rc.Lookup("a")
+	c.Check([]int{m.Start, m.End}, check.DeepEquals, []int{2, 6})
+	c.Check(m.Strand, check.Equals, seq.Minus)
+
+	rel, err := New("rel", 4, alphabet.DNA)
+	c.Assert(err, check.Equals, nil)
+	rel.Relaxed(true)
+	bl := NewBuilder(rel)
+	a = linear.NewSeq("a", alphabet.BytesToLetters([]byte("ACGT")), alphabet.DNA)
+	a.SetOffset(-3)
+	c.Check(bl.Insert(a), check.Equals, nil)
+	c.Check(bl.Snapshot().Start(), check.Equals, -3)
+	bl.NormalizeSnapshots(true)
+	snap := bl.Snapshot()
+	c.Check(snap.Start(), check.Equals, 0)
+	c.Check(fmt.Sprintf("%-s", snap), check.Equals, "ACGTnnn")
+	c.Check(bl.Do(func(c *Contig) error {
+		if c.Start() != -3 {
+			return fmt.Errorf("unexpected start %d", c.Start())
+		}
+		return nil
+	}), check.Equals, nil)
+	_, err = snap.Normalize()
+	c.Check(err, check.ErrorMatches, "contig: snapshot is read-only")
+}
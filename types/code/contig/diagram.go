@@ -0,0 +1,227 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"math"
+	"sort"
+	"strings"
+
+	"github.com/biogo/biogo/seq"
+)
+
+// diagramTick is the number of columns between ruler ticks of a text diagram.
+const diagramTick = 10
+
+// diagram holds the elements of a layout diagram of a Contig in its current
+// orientation. Intervals of a circular Contig that wrap its origin are split.
+type diagram struct {
+	members []diagramMember
+	gaps    []Interval
+	overlap []Interval
+}
+
+type diagramMember struct {
+	id     string
+	strand seq.Strand
+	spans  []Interval
+}
+
+// diagram returns the layout diagram elements of the Contig. Members are ordered
+// by start position.
+func (c *Contig) diagram() diagram {
+	var d diagram
+	split := func(start, end int) []Interval {
+		if c.IsCircular() && end > c.End() {
+			return []Interval{{start, c.End()}, {c.Start(), end - c.Len()}}
+		}
+		return []Interval{{start, end}}
+	}
+	for _, m := range c.Members() {
+		d.members = append(d.members, diagramMember{id: m.Seq.Name(), strand: m.Strand, spans: split(m.Start, m.End)})
+	}
+	sort.SliceStable(d.members, func(i, j int) bool { return d.members[i].spans[0].Start < d.members[j].spans[0].Start })
+	for _, g := range c.Gaps() {
+		d.gaps = append(d.gaps, split(g.Start, g.End)...)
+	}
+	cov, _ := c.Pileup(c.Start(), c.End())
+	for _, cv := range cov {
+		if cv.Depth() < 2 {
+			continue
+		}
+		if n := len(d.overlap); n != 0 && d.overlap[n-1].End == cv.Start {
+			d.overlap[n-1].End = cv.End
+			continue
+		}
+		d.overlap = append(d.overlap, Interval{cv.Start, cv.End})
+	}
+	return d
+}
+
+// WriteDiagram writes a text diagram of the layout of the Contig in its current
+// orientation to w, with each column representing scale positions. Each member is
+// drawn on its own line as an arrow pointing in the direction of its strand, with
+// a line marking gaps and a line marking positions covered by more than one
+// member.
+//
+// For example, a 60 base Contig with two overlapping members and a gap, drawn
+// at a scale of 2, is written as
+//
+//	scaffold [0,60) 2 positions/column
+//	        0         20        40
+//	        |---------|---------|---------
+//	a       ========>
+//	b              <=====
+//	gaps                        ======
+//	overlap        ==
+func (c *Contig) WriteDiagram(w io.Writer, scale int) error {
+	if scale < 1 {
+		return errors.New("contig: invalid scale")
+	}
+	d := c.diagram()
+	cols := (c.Len() + scale - 1) / scale
+	width := len("overlap")
+	for _, m := range d.members {
+		width = max(width, len(m.id))
+	}
+
+	row := func() []byte { return []byte(strings.Repeat(" ", cols)) }
+	col := func(i int) int { return min((i-c.Start())/scale, cols-1) }
+	fill := func(r []byte, s Interval) (from, to int) {
+		from, to = col(s.Start), col(s.End-1)
+		for i := from; i <= to; i++ {
+			r[i] = '='
+		}
+		return from, to
+	}
+
+	var b strings.Builder
+	line := func(label string, r []byte) {
+		fmt.Fprintf(&b, "%-*s %s\n", width, label, strings.TrimRight(string(r), " "))
+	}
+	fmt.Fprintf(&b, "%s [%d,%d) %d positions/column\n", c.ID, c.Start(), c.End(), scale)
+	if cols != 0 {
+		nums, ticks := row(), row()
+		for i := range ticks {
+			ticks[i] = '-'
+			if i%diagramTick != 0 {
+				continue
+			}
+			ticks[i] = '|'
+			if n := fmt.Sprint(c.Start() + i*scale); i+len(n) <= cols {
+				copy(nums[i:], n)
+			}
+		}
+		line("", nums)
+		line("", ticks)
+	}
+	for _, m := range d.members {
+		r := row()
+		for i, s := range m.spans {
+			from, to := fill(r, s)
+			switch {
+			case m.strand == seq.Plus && i == len(m.spans)-1:
+				r[to] = '>'
+			case m.strand == seq.Minus && i == 0:
+				r[from] = '<'
+			}
+		}
+		line(m.id, r)
+	}
+	for _, track := range []struct {
+		label string
+		spans []Interval
+	}{
+		{"gaps", d.gaps},
+		{"overlap", d.overlap},
+	} {
+		if len(track.spans) == 0 {
+			continue
+		}
+		r := row()
+		for _, s := range track.spans {
+			fill(r, s)
+		}
+		line(track.label, r)
+	}
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// SVG layout dimensions in pixels.
+const (
+	svgTrack  = 16
+	svgMargin = 8
+	svgLabel  = 96
+)
+
+// WriteSVG writes an SVG diagram of the layout of the Contig in its current
+// orientation to w, with scale positions per pixel. The diagram holds the same
+// elements as the text diagram written by WriteDiagram: members are drawn as
+// arrows on their own tracks, and gaps and overlaps are drawn on tracks below
+// them. Members and gaps carry a title giving their ID and interval.
+func (c *Contig) WriteSVG(w io.Writer, scale float64) error {
+	if scale <= 0 {
+		return errors.New("contig: invalid scale")
+	}
+	d := c.diagram()
+	x := func(i int) float64 { return svgLabel + float64(i-c.Start())/scale }
+	tracks := len(d.members) + 3
+	width := x(c.End()) + svgMargin
+	height := float64(tracks*svgTrack + 2*svgMargin)
+
+	var b strings.Builder
+	fmt.Fprintf(&b, `<svg xmlns="http://www.w3.org/2000/svg" width="%.0f" height="%.0f" font-family="monospace" font-size="10">`+"\n", width, height)
+	fmt.Fprintf(&b, "<title>%s [%d,%d)</title>\n", html.EscapeString(c.ID), c.Start(), c.End())
+	y := func(track int) float64 { return float64(svgMargin + track*svgTrack) }
+	label := func(track int, s string) {
+		fmt.Fprintf(&b, `<text x="0" y="%.1f">%s</text>`+"\n", y(track)+svgTrack*0.7, html.EscapeString(s))
+	}
+
+	label(0, c.ID)
+	fmt.Fprintf(&b, `<line x1="%.1f" y1="%.1f" x2="%.1f" y2="%.1f" stroke="black"/>`+"\n", x(c.Start()), y(0)+svgTrack/2, x(c.End()), y(0)+svgTrack/2)
+	for i, m := range d.members {
+		t := i + 1
+		label(t, m.id)
+		top, mid, bot := y(t)+2, y(t)+svgTrack/2, y(t)+svgTrack-2
+		for j, s := range m.spans {
+			x0, x1 := x(s.Start), x(s.End)
+			head := math.Min(svgTrack/2, x1-x0)
+			var points string
+			switch {
+			case m.strand == seq.Plus && j == len(m.spans)-1:
+				points = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f", x0, top, x1-head, top, x1, mid, x1-head, bot, x0, bot)
+			case m.strand == seq.Minus && j == 0:
+				points = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f", x0, mid, x0+head, top, x1, top, x1, bot, x0+head, bot)
+			default:
+				points = fmt.Sprintf("%.1f,%.1f %.1f,%.1f %.1f,%.1f %.1f,%.1f", x0, top, x1, top, x1, bot, x0, bot)
+			}
+			fmt.Fprintf(&b, `<polygon points="%s" fill="steelblue"><title>%s %s [%d,%d)</title></polygon>`+"\n",
+				points, html.EscapeString(m.id), m.strand, s.Start, s.End)
+		}
+	}
+	for i, track := range []struct {
+		label string
+		fill  string
+		spans []Interval
+	}{
+		{"gaps", "lightgrey", d.gaps},
+		{"overlap", "indianred", d.overlap},
+	} {
+		t := len(d.members) + 1 + i
+		label(t, track.label)
+		for _, s := range track.spans {
+			fmt.Fprintf(&b, `<rect x="%.1f" y="%.1f" width="%.1f" height="%d" fill="%s"><title>%s [%d,%d)</title></rect>`+"\n",
+				x(s.Start), y(t)+2, x(s.End)-x(s.Start), svgTrack-4, track.fill, track.label, s.Start, s.End)
+		}
+	}
+	b.WriteString("</svg>\n")
+	_, err := io.WriteString(w, b.String())
+	return err
+}
@@ -0,0 +1,230 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/biogo/biogo/seq"
+)
+
+// An EditKind specifies the kind of change described by an Edit.
+type EditKind int
+
+const (
+	MemberRemoved    EditKind = iota // A member present in the old Contig is absent from the new.
+	MemberAdded                      // A member absent from the old Contig is present in the new.
+	MemberMoved                      // A member has a different interval in the new Contig.
+	MemberReoriented                 // A member has a different strand in the new Contig.
+	GapRemoved                       // A gap present in the old Contig is absent from the new.
+	GapAdded                         // A gap absent from the old Contig is present in the new.
+	GapResized                       // A gap has a different length in the new Contig.
+)
+
+func (k EditKind) String() string {
+	switch k {
+	case MemberRemoved:
+		return "MemberRemoved"
+	case MemberAdded:
+		return "MemberAdded"
+	case MemberMoved:
+		return "MemberMoved"
+	case MemberReoriented:
+		return "MemberReoriented"
+	case GapRemoved:
+		return "GapRemoved"
+	case GapAdded:
+		return "GapAdded"
+	case GapResized:
+		return "GapResized"
+	}
+	return fmt.Sprintf("EditKind(%d)", int(k))
+}
+
+// An Edit describes a change between the layouts of two Contigs. Old and New
+// give the intervals of the changed member or gap in the current coordinates
+// of the old and new Contig, and are zero when the member or gap is absent
+// from that Contig. For member edits, ID is the member's ID and OldStrand and
+// NewStrand its strands relative to each Contig. For gap edits, Left and Right
+// are the IDs of the nearest members ending before and starting after the gap,
+// and are empty at the ends of the Contig.
+type Edit struct {
+	Kind EditKind
+
+	ID                   string
+	OldStrand, NewStrand seq.Strand
+
+	Left, Right string
+
+	Old, New Interval
+}
+
+func (e Edit) String() string {
+	switch e.Kind {
+	case MemberRemoved:
+		return fmt.Sprintf("- member %s [%d,%d) %v", e.ID, e.Old.Start, e.Old.End, e.OldStrand)
+	case MemberAdded:
+		return fmt.Sprintf("+ member %s [%d,%d) %v", e.ID, e.New.Start, e.New.End, e.NewStrand)
+	case MemberMoved:
+		return fmt.Sprintf("~ member %s moved [%d,%d) -> [%d,%d)", e.ID, e.Old.Start, e.Old.End, e.New.Start, e.New.End)
+	case MemberReoriented:
+		return fmt.Sprintf("~ member %s reoriented %v -> %v", e.ID, e.OldStrand, e.NewStrand)
+	case GapRemoved:
+		return fmt.Sprintf("- gap [%d,%d) %s", e.Old.Start, e.Old.End, e.flanks())
+	case GapAdded:
+		return fmt.Sprintf("+ gap [%d,%d) %s", e.New.Start, e.New.End, e.flanks())
+	case GapResized:
+		return fmt.Sprintf("~ gap resized %d -> %d [%d,%d) -> [%d,%d) %s",
+			e.Old.End-e.Old.Start, e.New.End-e.New.Start, e.Old.Start, e.Old.End, e.New.Start, e.New.End, e.flanks())
+	}
+	return fmt.Sprintf("%v", e.Kind)
+}
+
+func (e Edit) flanks() string {
+	name := func(id string) string {
+		if id == "" {
+			return "end"
+		}
+		return id
+	}
+	return fmt.Sprintf("between %s and %s", name(e.Left), name(e.Right))
+}
+
+// Diff returns the edits that transform the layout of the Contig from into that
+// of the Contig to. Members are matched by ID and gaps by the IDs of their
+// flanking members, in order of position. Gaps include runs of ground state
+// positions not covered by a Gap, as written by AGPWriter. Edits are ordered by
+// kind and then by position.
+func Diff(from, to *Contig) []Edit {
+	var edits []Edit
+
+	om, nm := from.Members(), to.Members()
+	nIdx := make(map[string]Member, len(nm))
+	for _, m := range nm {
+		nIdx[m.Seq.Name()] = m
+	}
+	oIdx := make(map[string]bool, len(om))
+	for _, o := range om {
+		id := o.Seq.Name()
+		oIdx[id] = true
+		oi := Interval{Start: o.Start, End: o.End}
+		n, ok := nIdx[id]
+		if !ok {
+			edits = append(edits, Edit{Kind: MemberRemoved, ID: id, OldStrand: o.Strand, Old: oi})
+			continue
+		}
+		ni := Interval{Start: n.Start, End: n.End}
+		if oi != ni {
+			edits = append(edits, Edit{Kind: MemberMoved, ID: id, OldStrand: o.Strand, NewStrand: n.Strand, Old: oi, New: ni})
+		}
+		if o.Strand != n.Strand {
+			edits = append(edits, Edit{Kind: MemberReoriented, ID: id, OldStrand: o.Strand, NewStrand: n.Strand, Old: oi, New: ni})
+		}
+	}
+	for _, n := range nm {
+		if id := n.Seq.Name(); !oIdx[id] {
+			edits = append(edits, Edit{Kind: MemberAdded, ID: id, NewStrand: n.Strand, New: Interval{Start: n.Start, End: n.End}})
+		}
+	}
+
+	type flank struct{ left, right string }
+	oldGaps := make(map[flank][]Interval)
+	var oldKeys []flank
+	for _, oi := range from.tileGaps() {
+		k := flank{}
+		k.left, k.right = flanking(om, oi)
+		if _, ok := oldGaps[k]; !ok {
+			oldKeys = append(oldKeys, k)
+		}
+		oldGaps[k] = append(oldGaps[k], oi)
+	}
+	for _, ni := range to.tileGaps() {
+		k := flank{}
+		k.left, k.right = flanking(nm, ni)
+		if len(oldGaps[k]) == 0 {
+			edits = append(edits, Edit{Kind: GapAdded, Left: k.left, Right: k.right, New: ni})
+			continue
+		}
+		oi := oldGaps[k][0]
+		oldGaps[k] = oldGaps[k][1:]
+		if oi.End-oi.Start != ni.End-ni.Start {
+			edits = append(edits, Edit{Kind: GapResized, Left: k.left, Right: k.right, Old: oi, New: ni})
+		}
+	}
+	for _, k := range oldKeys {
+		for _, oi := range oldGaps[k] {
+			edits = append(edits, Edit{Kind: GapRemoved, Left: k.left, Right: k.right, Old: oi})
+		}
+	}
+
+	sort.Stable(byKind(edits))
+	return edits
+}
+
+// tileGaps returns the intervals of the Contig that are not provided by a
+// member, as tiled by walkTiles.
+func (c *Contig) tileGaps() []Interval {
+	var gaps []Interval
+	c.walkTiles(func(start, end int, m *placement) {
+		if m == nil {
+			gaps = append(gaps, Interval{Start: start, End: end})
+		}
+	})
+	return gaps
+}
+
+// flanking returns the IDs of the members ending nearest before and starting
+// nearest after g.
+func flanking(m []Member, g Interval) (left, right string) {
+	le, rs := 0, 0
+	for _, m := range m {
+		if m.End <= g.Start && (left == "" || m.End > le) {
+			left, le = m.Seq.Name(), m.End
+		}
+		if m.Start >= g.End && (right == "" || m.Start < rs) {
+			right, rs = m.Seq.Name(), m.Start
+		}
+	}
+	return left, right
+}
+
+type byKind []Edit
+
+func (e byKind) Len() int { return len(e) }
+func (e byKind) Less(i, j int) bool {
+	if e[i].Kind != e[j].Kind {
+		return e[i].Kind < e[j].Kind
+	}
+	return e[i].pos() < e[j].pos()
+}
+func (e byKind) Swap(i, j int) { e[i], e[j] = e[j], e[i] }
+
+// pos returns the position used to order the edit.
+func (e Edit) pos() int {
+	if e.Kind == MemberRemoved || e.Kind == GapRemoved {
+		return e.Old.Start
+	}
+	return e.New.Start
+}
+
+// WriteDiff writes to w a report of the edits that transform the layout of the
+// Contig from into that of the Contig to. The report starts with lines naming the
+// old and new Contigs and their intervals, followed by one line for each edit as
+// returned by Diff.
+func WriteDiff(w io.Writer, from, to *Contig) error {
+	_, err := fmt.Fprintf(w, "--- %s [%d,%d)\n+++ %s [%d,%d)\n", from.ID, from.Start(), from.End(), to.ID, to.Start(), to.End())
+	if err != nil {
+		return err
+	}
+	for _, e := range Diff(from, to) {
+		_, err = fmt.Fprintln(w, e)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,633 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/biogo/seq/linear"
+	"github.com/biogo/store/step"
+)
+
+// A PatchOp specifies the kind of edit made by a Patch.
+type PatchOp int
+
+const (
+	Substitution PatchOp = iota // Letters replace the letters starting at Pos.
+	Insertion                   // Letters are inserted before the letter at Pos.
+	Deletion                    // The letters starting at Pos are deleted.
+)
+
+func (o PatchOp) String() string {
+	switch o {
+	case Substitution:
+		return "Substitution"
+	case Insertion:
+		return "Insertion"
+	case Deletion:
+		return "Deletion"
+	}
+	return fmt.Sprintf("PatchOp(%d)", int(o))
+}
+
+// A Patch is an edit made to a Contig by an Editor. Pos is in the Contig's
+// coordinates at the time of the edit and Letters are in the Contig's orientation.
+// For a Deletion, Letters holds the deleted letters.
+//
+// If the letter at Pos was provided by a member when the edit was made, Member
+// holds the ID of the member, MemberPos the corresponding position in member
+// coordinates and Strand the relative strand of the member. Applying the Patch
+// to a Contig holding the member locates the edit by the member, so a patch log
+// can be reapplied after the Contig is re-assembled.
+type Patch struct {
+	Op      PatchOp
+	Pos     int
+	Letters alphabet.Letters
+
+	Member    string
+	MemberPos int
+	Strand    seq.Strand
+}
+
+// An Editor records edits made to a Contig as a patch log that can be undone,
+// redone and replayed onto another Contig. Edits do not alter the sequences
+// held by the Contig's members when the edit is made; edited members are given
+// an edited copy of their sequence. Letters substituted or inserted at positions
+// not covered by a member are held by new members. The Contig should not be
+// altered other than through the Editor while edits are being made.
+type Editor struct {
+	c    *Contig
+	log  []Patch
+	undo []contigState
+	redo []Patch
+}
+
+// NewEditor returns a new Editor that edits c.
+func NewEditor(c *Contig) *Editor { return &Editor{c: c} }
+
+// Contig returns the Contig edited by the Editor.
+func (e *Editor) Contig() *Contig { return e.c }
+
+// Log returns the patches that have been applied and not undone, in order.
+func (e *Editor) Log() []Patch { return append([]Patch(nil), e.log...) }
+
+// Substitute replaces the letters of the Contig starting at position pos with l.
+func (e *Editor) Substitute(pos int, l alphabet.Letters) error {
+	return e.edit(Patch{Op: Substitution, Pos: pos, Letters: l}, true)
+}
+
+// Insert inserts l into the Contig before the letter at position pos. If pos is
+// the Contig's end, l is appended to the Contig.
+func (e *Editor) Insert(pos int, l alphabet.Letters) error {
+	return e.edit(Patch{Op: Insertion, Pos: pos, Letters: l}, true)
+}
+
+// Delete deletes the letters of the interval [start, end) from the Contig.
+func (e *Editor) Delete(start, end int) error {
+	if start >= end || start < e.c.Start() || end > e.c.End() {
+		return errors.New("contig: edit out of range")
+	}
+	l := make(alphabet.Letters, 0, end-start)
+	for i := start; i < end; i++ {
+		l = append(l, e.c.At(i).L)
+	}
+	return e.edit(Patch{Op: Deletion, Pos: start, Letters: l}, true)
+}
+
+// Apply applies p to the Contig. If the member named by p is held by the Contig,
+// the edit is located by the member position and strand recorded in p, otherwise
+// it is made at p.Pos.
+func (e *Editor) Apply(p Patch) error {
+	if p.Member == "" {
+		return e.edit(p, true)
+	}
+	i, strand, err := e.c.FromMember(p.Member, p.MemberPos)
+	if err != nil {
+		return e.edit(p, true)
+	}
+	p.Pos = i
+	if strand != p.Strand {
+		// The member is in the opposite orientation, so the
+		// edit extends in the other direction from i.
+		l := make(alphabet.Letters, len(p.Letters))
+		for j, b := range p.Letters {
+			l[len(l)-1-j] = complement(e.c.Alpha, b)
+		}
+		p.Letters = l
+		if p.Op == Insertion {
+			p.Pos++
+		} else {
+			p.Pos -= len(l) - 1
+		}
+	}
+	return e.edit(p, true)
+}
+
+// Undo reverts the most recently applied patch that has not been undone.
+func (e *Editor) Undo() error {
+	n := len(e.undo)
+	if n == 0 {
+		return errors.New("contig: nothing to undo")
+	}
+	err := e.c.restore(e.undo[n-1])
+	if err != nil {
+		return err
+	}
+	e.redo = append(e.redo, e.log[n-1])
+	e.undo, e.log = e.undo[:n-1], e.log[:n-1]
+	return nil
+}
+
+// Redo reapplies the most recently undone patch. Making a new edit discards the
+// undone patches.
+func (e *Editor) Redo() error {
+	n := len(e.redo)
+	if n == 0 {
+		return errors.New("contig: nothing to redo")
+	}
+	err := e.edit(e.redo[n-1], false)
+	if err != nil {
+		return err
+	}
+	e.redo = e.redo[:n-1]
+	return nil
+}
+
+// edit applies p at p.Pos, recording the member anchor of the edit. If discard
+// is true, undone patches are discarded.
+func (e *Editor) edit(p Patch, discard bool) error {
+	c := e.c
+	if c.frozen {
+		return errSnapshot
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	if len(p.Letters) == 0 {
+		return errors.New("contig: empty patch")
+	}
+	p.Member, p.MemberPos, p.Strand = "", 0, seq.None
+	if m, pos, strand, err := c.ToMember(p.Pos); err == nil {
+		p.Member, p.MemberPos, p.Strand = m.Name(), pos, strand
+	}
+	st := c.state()
+	var err error
+	switch p.Op {
+	case Substitution:
+		err = c.substitute(p.Pos, p.Letters)
+	case Insertion:
+		err = c.insertLetters(p.Pos, p.Letters)
+	case Deletion:
+		err = c.deleteLetters(p.Pos, p.Pos+len(p.Letters))
+	default:
+		err = errors.New("contig: invalid patch operation")
+	}
+	if err != nil {
+		if rerr := c.restore(st); rerr != nil {
+			return rerr
+		}
+		return err
+	}
+	e.log = append(e.log, p)
+	e.undo = append(e.undo, st)
+	if discard {
+		e.redo = nil
+	}
+	return nil
+}
+
+// contigState is a record of the layout of a Contig. Member sequences are not
+// recorded since edits replace rather than alter them.
+type contigState struct {
+	view     frame
+	shift    int
+	start    int
+	end      int
+	members  []*placement
+	placed   []placement
+	gaps     []Gap
+	features []annot
+	masks    []Interval
+}
+
+func (c *Contig) state() contigState {
+	s := contigState{
+		view:    c.view,
+		shift:   c.shift,
+		start:   c.vector.Start(),
+		end:     c.vector.End(),
+		members: append([]*placement(nil), c.members...),
+		gaps:    c.baseGaps(),
+		masks:   append([]Interval(nil), c.masks...),
+	}
+	for _, p := range c.members {
+		s.placed = append(s.placed, *p)
+	}
+	for _, a := range c.features {
+		s.features = append(s.features, *a)
+	}
+	return s
+}
+
+func (c *Contig) restore(s contigState) error {
+	c.view, c.shift = s.view, s.shift
+	for i, p := range s.members {
+		*p = s.placed[i]
+	}
+	c.members = append([]*placement(nil), s.members...)
+	c.index = make(map[string]*placement, len(s.members))
+	c.features = make([]*annot, len(s.features))
+	for i := range s.features {
+		a := s.features[i]
+		c.features[i] = &a
+	}
+	c.masks = append([]Interval(nil), s.masks...)
+	return c.reset(s.start, s.end, s.gaps)
+}
+
+// patchID returns an unused member ID for a member holding edited letters.
+func (c *Contig) patchID() string {
+	for i := len(c.members); ; i++ {
+		id := fmt.Sprintf("%s.patch%d", c.ID, i)
+		if _, ok := c.index[id]; !ok {
+			return id
+		}
+	}
+}
+
+// addPatch places a new member holding l at base position start.
+func (c *Contig) addPatch(start int, l alphabet.Letters) error {
+	s := linear.NewSeq(c.patchID(), append(alphabet.Letters(nil), l...), c.Alpha)
+	p := &placement{s: s, start: start}
+	if c.IsCircular() {
+		p.wrap = c.Len()
+	}
+	return c.insert(p)
+}
+
+// checkWrap returns an error if the Contig has a member spanning the origin
+// of a circular Contig.
+func (c *Contig) checkWrap() error {
+	for _, p := range c.members {
+		if p.wrap != 0 && p.end() > c.vector.End() {
+			return errors.New("contig: cannot change length of contig with member spanning origin")
+		}
+	}
+	return nil
+}
+
+// substitute replaces the letters of the Contig starting at position pos with l.
+func (c *Contig) substitute(pos int, l alphabet.Letters) error {
+	if pos < c.Start() || pos+len(l) > c.End() {
+		return errors.New("contig: edit out of range")
+	}
+	err := c.Materialize()
+	if err != nil {
+		return err
+	}
+	type run struct {
+		start, end int
+		e          step.Equaler
+	}
+	var runs []run
+	c.vector.DoRange(pos, pos+len(l), func(start, end int, e step.Equaler) {
+		runs = append(runs, run{start, end, e})
+	})
+	owned := make(map[*placement]bool)
+	for _, r := range runs {
+		ss, ok := r.e.(seqStep)
+		if !ok {
+			err = c.addPatch(r.start, l[r.start-pos:r.end-pos])
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		for _, p := range ss {
+			if !owned[p] {
+				p.s, owned[p] = p.s.Clone(), true
+			}
+			for i := r.start; i < r.end; i++ {
+				j := p.s.Start() + p.offset(i)
+				ml := l[i-pos]
+				if p.complemented {
+					ml = complement(c.Alpha, ml)
+				}
+				err = p.s.Set(j, alphabet.QLetter{L: ml, Q: p.s.At(j).Q})
+				if err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// insertLetters inserts l before the letter at position pos of the Contig.
+// Members covering both sides of pos have l inserted into a copy of their
+// sequence, otherwise l is held by a new member.
+func (c *Contig) insertLetters(pos int, l alphabet.Letters) error {
+	if pos < c.Start() || pos > c.End() {
+		return errors.New("contig: edit out of range")
+	}
+	err := c.checkWrap()
+	if err != nil {
+		return err
+	}
+	err = c.Materialize()
+	if err != nil {
+		return err
+	}
+	n := len(l)
+	var split bool
+	for _, p := range c.members {
+		ms, me := p.start, p.end()
+		switch {
+		case ms >= pos:
+			p.start += n
+		case me > pos:
+			split = true
+			ml, off := c.memberLetters(p, l), pos-ms
+			if p.reversed {
+				off = me - pos
+			}
+			p.s = p.s.Clone()
+			splice(p.s, off, 0, ml)
+			for _, a := range c.features {
+				if a.anchor == p {
+					a.start, a.end = insertShift(a.start, a.end, off, n)
+				}
+			}
+		}
+	}
+	var gaps []Gap
+	for _, g := range c.baseGaps() {
+		if g.Start < pos && pos < g.End {
+			h := g
+			g.End, h.Start, h.End = pos, pos+n, h.End+n
+			gaps = append(gaps, g, h)
+			continue
+		}
+		g.Start, g.End = insertShift(g.Start, g.End, pos, n)
+		gaps = append(gaps, g)
+	}
+	for _, a := range c.features {
+		if a.anchor == nil {
+			a.start, a.end = insertShift(a.start, a.end, pos, n)
+		}
+	}
+	masks := c.masks
+	c.masks = nil
+	for _, r := range masks {
+		c.addMask(insertShift(r.Start, r.End, pos, n))
+	}
+	err = c.resize(c.vector.Start(), c.vector.End()+n, gaps)
+	if err != nil || split {
+		return err
+	}
+	return c.addPatch(pos, l)
+}
+
+// deleteLetters deletes the letters of the interval [start, end) from the Contig.
+// Members within the interval are removed and members partially within the
+// interval have the deleted letters removed from a copy of their sequence.
+func (c *Contig) deleteLetters(start, end int) error {
+	if start >= end || start < c.Start() || end > c.End() {
+		return errors.New("contig: edit out of range")
+	}
+	n := end - start
+	if n == c.Len() {
+		return errors.New("contig: cannot delete entire contig")
+	}
+	err := c.checkWrap()
+	if err != nil {
+		return err
+	}
+	err = c.Materialize()
+	if err != nil {
+		return err
+	}
+	at := func(i int) int { return deleteShift(i, start, end) }
+	members := c.members[:0]
+	for _, p := range c.members {
+		ms, me := p.start, p.end()
+		switch {
+		case me <= start:
+		case ms >= end:
+			p.start -= n
+		case ms >= start && me <= end:
+			delete(c.index, p.s.Name())
+			c.dropFeatures(p)
+			continue
+		default:
+			os, oe := max(ms, start), min(me, end)
+			off := os - ms
+			if p.reversed {
+				off = me - oe
+			}
+			p.s = p.s.Clone()
+			splice(p.s, off, oe-os, nil)
+			for _, a := range c.features {
+				if a.anchor == p {
+					a.start, a.end = deleteShift(a.start, off, off+oe-os), deleteShift(a.end, off, off+oe-os)
+				}
+			}
+			p.start = at(ms)
+		}
+		members = append(members, p)
+	}
+	c.members = members
+	var gaps []Gap
+	for _, g := range c.baseGaps() {
+		g.Start, g.End = at(g.Start), at(g.End)
+		if g.Start < g.End {
+			gaps = append(gaps, g)
+		}
+	}
+	features := c.features[:0]
+	for _, a := range c.features {
+		if a.anchor == nil {
+			empty := a.start == a.end
+			a.start, a.end = at(a.start), at(a.end)
+			if a.start == a.end && !empty {
+				continue
+			}
+		}
+		features = append(features, a)
+	}
+	c.features = features
+	masks := c.masks
+	c.masks = nil
+	for _, r := range masks {
+		c.addMask(at(r.Start), at(r.End))
+	}
+	return c.resize(c.vector.Start(), c.vector.End()-n, gaps)
+}
+
+// resize reconstructs the step vector of a materialized Contig over the base
+// interval [start, end), updating the wrap length of members of a circular
+// Contig.
+func (c *Contig) resize(start, end int, gaps []Gap) error {
+	if c.IsCircular() {
+		for _, p := range c.members {
+			p.wrap = end - start
+		}
+	}
+	return c.reset(start, end, gaps)
+}
+
+// memberLetters returns the letters l given in the Contig's orientation in the
+// orientation of the member p.
+func (c *Contig) memberLetters(p *placement, l alphabet.Letters) alphabet.Letters {
+	ml := make(alphabet.Letters, len(l))
+	for i, b := range l {
+		if p.complemented {
+			b = complement(c.Alpha, b)
+		}
+		if p.reversed {
+			ml[len(l)-1-i] = b
+		} else {
+			ml[i] = b
+		}
+	}
+	return ml
+}
+
+// splice replaces the del letters of s starting at offset off from the start
+// of s with l. Inserted letters are given the default quality if s holds
+// quality scores.
+func splice(s seq.Sequence, off, del int, l alphabet.Letters) {
+	sl := s.Slice()
+	var ins alphabet.Slice = l
+	if _, ok := sl.(alphabet.QLetters); ok {
+		ql := make(alphabet.QLetters, len(l))
+		for i, b := range l {
+			ql[i] = alphabet.QLetter{L: b, Q: seq.DefaultQphred}
+		}
+		ins = ql
+	}
+	out := sl.Make(0, sl.Len()-del+ins.Len())
+	out = out.Append(sl.Slice(0, off))
+	out = out.Append(ins)
+	out = out.Append(sl.Slice(off+del, sl.Len()))
+	s.SetSlice(out)
+}
+
+// insertShift returns the interval [start, end) after n positions are inserted
+// before position at. An interval starting at at is moved and an interval
+// spanning at is extended.
+func insertShift(start, end, at, n int) (int, int) {
+	if end > at || start >= at {
+		end += n
+	}
+	if start >= at {
+		start += n
+	}
+	return start, end
+}
+
+// deleteShift returns position i after the positions [start, end) are deleted.
+func deleteShift(i, start, end int) int {
+	switch {
+	case i <= start:
+		return i
+	case i < end:
+		return start
+	}
+	return i - (end - start)
+}
+
+// WritePatches writes the patches in p to w in a tab-delimited text format with
+// one patch per line giving the operation, position, letters, member ID, member
+// position and strand. Operations are written as S, I and D, and absent members
+// as a dot.
+func WritePatches(w io.Writer, p []Patch) error {
+	bw := bufio.NewWriter(w)
+	for _, p := range p {
+		var op byte
+		switch p.Op {
+		case Substitution:
+			op = 'S'
+		case Insertion:
+			op = 'I'
+		case Deletion:
+			op = 'D'
+		default:
+			return fmt.Errorf("contig: invalid patch operation %v", p.Op)
+		}
+		m := p.Member
+		if m == "" {
+			m = "."
+		}
+		fmt.Fprintf(bw, "%c\t%d\t%s\t%s\t%d\t%v\n", op, p.Pos, alphabet.LettersToBytes(p.Letters), m, p.MemberPos, p.Strand)
+	}
+	return bw.Flush()
+}
+
+// ReadPatches reads patches written by WritePatches from r. Blank lines and lines
+// starting with '#' are ignored.
+func ReadPatches(r io.Reader) ([]Patch, error) {
+	var patches []Patch
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		f := strings.Split(line, "\t")
+		if len(f) != 6 {
+			return nil, fmt.Errorf("contig: patch line %d: wrong number of fields", n)
+		}
+		var p Patch
+		switch f[0] {
+		case "S":
+			p.Op = Substitution
+		case "I":
+			p.Op = Insertion
+		case "D":
+			p.Op = Deletion
+		default:
+			return nil, fmt.Errorf("contig: patch line %d: invalid operation %q", n, f[0])
+		}
+		var err error
+		p.Pos, err = strconv.Atoi(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("contig: patch line %d: %v", n, err)
+		}
+		if f[2] == "" {
+			return nil, fmt.Errorf("contig: patch line %d: no letters", n)
+		}
+		p.Letters = alphabet.BytesToLetters([]byte(f[2]))
+		if f[3] != "." {
+			p.Member = f[3]
+		}
+		p.MemberPos, err = strconv.Atoi(f[4])
+		if err != nil {
+			return nil, fmt.Errorf("contig: patch line %d: %v", n, err)
+		}
+		switch f[5] {
+		case "+":
+			p.Strand = seq.Plus
+		case "-":
+			p.Strand = seq.Minus
+		case ".":
+			p.Strand = seq.None
+		default:
+			return nil, fmt.Errorf("contig: patch line %d: invalid strand %q", n, f[5])
+		}
+		patches = append(patches, p)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return patches, nil
+}
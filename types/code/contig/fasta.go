@@ -0,0 +1,55 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"io"
+
+	"github.com/biogo/store/step"
+)
+
+// FASTAWriter writes the letters of Contigs in FASTA format through a buffer.
+// Letters are written as they are resolved from the Contig's steps, so no copy
+// of a Contig's sequence is made.
+type FASTAWriter struct {
+	w     *bufio.Writer
+	width int
+}
+
+// NewFASTAWriter returns a new FASTAWriter that writes to w wrapping sequence
+// lines at width letters. If width is zero or less, sequences are written on
+// a single line.
+func NewFASTAWriter(w io.Writer, width int) *FASTAWriter {
+	return &FASTAWriter{w: bufio.NewWriter(w), width: width}
+}
+
+// Write writes c in its current orientation with overlapping positions resolved
+// according to its Policy and masks rendered according to its Masking. The buffer
+// is flushed after each Contig is written.
+func (w *FASTAWriter) Write(c *Contig) error {
+	w.w.WriteByte('>')
+	w.w.WriteString(c.ID)
+	if c.Desc != "" {
+		w.w.WriteByte(' ')
+		w.w.WriteString(c.Desc)
+	}
+	w.w.WriteByte('\n')
+	if c.vector != nil && c.Len() != 0 {
+		var n int
+		c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+			for i := start; i < end; i++ {
+				if w.width > 0 && n == w.width {
+					w.w.WriteByte('\n')
+					n = 0
+				}
+				w.w.WriteByte(byte(c.letter(i, e).L))
+				n++
+			}
+		})
+		w.w.WriteByte('\n')
+	}
+	return w.w.Flush()
+}
@@ -0,0 +1,224 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/biogo/biogo/feat"
+)
+
+// A Placement describes the position of a feature attached to a Contig. Start,
+// End and Orientation are given in the Contig's current coordinates.
+type Placement struct {
+	Feature     feat.Feature
+	Start, End  int
+	Orientation feat.Orientation
+
+	// Anchor is the ID of the member the feature is anchored
+	// to, or empty if the feature is placed in Contig
+	// coordinates.
+	Anchor string
+}
+
+// annot is a feature attached to a Contig. If anchor is nil, start, end and
+// orient are relative to the base coordinates of the Contig, otherwise they
+// are in member coordinates and relative to the member as it was inserted.
+type annot struct {
+	f          feat.Feature
+	anchor     *placement
+	start, end int
+	orient     feat.Orientation
+}
+
+func orientation(f feat.Feature) feat.Orientation {
+	if o, ok := f.(feat.Orienter); ok {
+		return o.Orientation()
+	}
+	return feat.NotOriented
+}
+
+// Annotate attaches the feature f to the Contig at the position given by f's Start
+// and End in Contig coordinates. If f is a feat.Orienter its orientation is retained
+// relative to the Contig. Attached features are moved and reoriented by RevComp and
+// Reverse.
+func (c *Contig) Annotate(f feat.Feature) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if f.Start() < c.Start() || f.End() > c.End() || f.Start() > f.End() {
+		return errors.New("contig: feature out of range")
+	}
+	c.attach(f, f.Start(), f.End(), orientation(f))
+	return nil
+}
+
+// attach attaches f to the Contig at [start, end) with orientation o, all in
+// the Contig's current coordinates.
+func (c *Contig) attach(f feat.Feature, start, end int, o feat.Orientation) {
+	a := &annot{f: f, orient: o}
+	a.start, a.end = c.toBase(start, end)
+	if c.view.reversed {
+		a.orient = -a.orient
+	}
+	c.features = append(c.features, a)
+}
+
+// AnnotateMember attaches the feature f to the member with the given ID at the
+// position given by f's Start and End in member coordinates, as described for
+// Segment. Anchored features follow the member when the Contig is reoriented and
+// are removed with the member.
+func (c *Contig) AnnotateMember(id string, f feat.Feature) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	m, ok := c.index[id]
+	if !ok {
+		return errors.New("contig: no member with ID")
+	}
+	if f.Start() < 0 || f.End() > m.s.Len() || f.Start() > f.End() {
+		return errors.New("contig: feature out of range")
+	}
+	c.features = append(c.features, &annot{
+		f:      f,
+		anchor: m,
+		start:  f.Start(),
+		end:    f.End(),
+		orient: orientation(f),
+	})
+	return nil
+}
+
+// Features returns the placements of the features attached to the Contig sorted
+// by start position.
+func (c *Contig) Features() []Placement {
+	p := make([]Placement, len(c.features))
+	for i, a := range c.features {
+		p[i] = c.place(a)
+	}
+	sort.Stable(byStart(p))
+	return p
+}
+
+type byStart []Placement
+
+func (p byStart) Len() int           { return len(p) }
+func (p byStart) Less(i, j int) bool { return p[i].Start < p[j].Start }
+func (p byStart) Swap(i, j int)      { p[i], p[j] = p[j], p[i] }
+
+// place returns the placement of a in the Contig's current coordinates.
+func (c *Contig) place(a *annot) Placement {
+	p := Placement{Feature: a.f, Orientation: a.orient}
+	start, end := a.start, a.end
+	if m := a.anchor; m != nil {
+		p.Anchor = m.s.Name()
+		if m.reversed {
+			start, end = m.s.Len()-end, m.s.Len()-start
+			p.Orientation = -p.Orientation
+		}
+		start += m.start
+		end += m.start
+	}
+	p.Start, p.End = c.interval(start, end)
+	if c.view.reversed {
+		p.Orientation = -p.Orientation
+	}
+	return p
+}
+
+// rebaseFeatures places the features held in base coordinates in the Contig's
+// current coordinates.
+func (c *Contig) rebaseFeatures() {
+	for _, a := range c.features {
+		if a.anchor == nil {
+			p := c.place(a)
+			a.start, a.end, a.orient = p.Start, p.End, p.Orientation
+		}
+	}
+}
+
+// dropFeatures removes the features anchored to p.
+func (c *Contig) dropFeatures(p *placement) {
+	kept := c.features[:0]
+	for _, a := range c.features {
+		if a.anchor != p {
+			kept = append(kept, a)
+		}
+	}
+	for i := len(kept); i < len(c.features); i++ {
+		c.features[i] = nil
+	}
+	c.features = kept
+}
+
+// GFFWriter writes the features attached to Contigs in GFF3 format.
+type GFFWriter struct {
+	w      io.Writer
+	header bool
+
+	// Source is written in the source column
+	// of each feature line if not empty.
+	Source string
+}
+
+// NewGFFWriter returns a new GFFWriter that writes to w.
+func NewGFFWriter(w io.Writer) *GFFWriter {
+	return &GFFWriter{w: w}
+}
+
+// Write writes the features attached to c as GFF3 feature lines with the ID of c
+// as the sequence ID. The Description of each feature is used as its type and its
+// Name as its ID attribute. An anchor attribute is written for features anchored
+// to a member.
+func (w *GFFWriter) Write(c *Contig) error {
+	if !w.header {
+		_, err := fmt.Fprintln(w.w, "##gff-version 3")
+		if err != nil {
+			return err
+		}
+		w.header = true
+	}
+	source := w.Source
+	if source == "" {
+		source = "."
+	}
+	for _, p := range c.Features() {
+		strand := '.'
+		switch p.Orientation {
+		case feat.Forward:
+			strand = '+'
+		case feat.Reverse:
+			strand = '-'
+		}
+		attr := "ID=" + gffEscape(p.Feature.Name())
+		if p.Anchor != "" {
+			attr += ";anchor=" + gffEscape(p.Anchor)
+		}
+		_, err := fmt.Fprintf(w.w, "%s\t%s\t%s\t%d\t%d\t.\t%c\t.\t%s\n",
+			gffEscape(c.ID), source, p.Feature.Description(), feat.ZeroToOne(p.Start), p.End, strand, attr)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// gffEscape percent-encodes control characters and characters with reserved
+// meaning in GFF3 columns and attribute values.
+func gffEscape(s string) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		if c := s[i]; c < ' ' || c == 0x7f || strings.IndexByte("%;=&,", c) >= 0 {
+			fmt.Fprintf(&b, "%%%02X", c)
+		} else {
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,283 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/biogo/store/step"
+)
+
+// DefaultUnknownGap is the conventional length of a gap of unknown size.
+const DefaultUnknownGap = 100
+
+// A GapType specifies the nature of a gap in a Contig. GapType values correspond
+// to the AGP 2.1 gap types.
+type GapType int
+
+const (
+	ScaffoldGap GapType = iota
+	ContigGap
+	Centromere
+	ShortArm
+	Heterochromatin
+	Telomere
+	Repeat
+	Contamination
+	UnknownGap
+)
+
+var gapTypes = [...]string{
+	ScaffoldGap:     "scaffold",
+	ContigGap:       "contig",
+	Centromere:      "centromere",
+	ShortArm:        "short_arm",
+	Heterochromatin: "heterochromatin",
+	Telomere:        "telomere",
+	Repeat:          "repeat",
+	Contamination:   "contamination",
+	UnknownGap:      "unknown",
+}
+
+func (t GapType) String() string {
+	if t < 0 || int(t) >= len(gapTypes) {
+		return fmt.Sprintf("GapType(%d)", int(t))
+	}
+	return gapTypes[t]
+}
+
+func parseGapType(s string) (GapType, error) {
+	for t, n := range gapTypes {
+		if n == s {
+			return GapType(t), nil
+		}
+	}
+	return 0, fmt.Errorf("contig: unknown gap type %q", s)
+}
+
+// An Evidence specifies a kind of evidence supporting linkage across a gap. Evidence
+// values correspond to the AGP 2.1 linkage evidence types.
+type Evidence int
+
+const (
+	PairedEnds Evidence = iota
+	AlignGenus
+	AlignXGenus
+	AlignTranscript
+	WithinClone
+	CloneContig
+	Map
+	PCR
+	ProximityLigation
+	Strobe
+	Unspecified
+)
+
+var evidenceTypes = [...]string{
+	PairedEnds:        "paired-ends",
+	AlignGenus:        "align_genus",
+	AlignXGenus:       "align_xgenus",
+	AlignTranscript:   "align_trnscpt",
+	WithinClone:       "within_clone",
+	CloneContig:       "clone_contig",
+	Map:               "map",
+	PCR:               "pcr",
+	ProximityLigation: "proximity_ligation",
+	Strobe:            "strobe",
+	Unspecified:       "unspecified",
+}
+
+func (e Evidence) String() string {
+	if e < 0 || int(e) >= len(evidenceTypes) {
+		return fmt.Sprintf("Evidence(%d)", int(e))
+	}
+	return evidenceTypes[e]
+}
+
+// formatEvidence returns the AGP representation of the evidence list ev.
+func formatEvidence(ev []Evidence) string {
+	if len(ev) == 0 {
+		return "na"
+	}
+	s := make([]string, len(ev))
+	for i, e := range ev {
+		s[i] = e.String()
+	}
+	return strings.Join(s, ";")
+}
+
+// parseEvidence parses the AGP representation of an evidence list.
+func parseEvidence(s string) ([]Evidence, error) {
+	if s == "na" {
+		return nil, nil
+	}
+	var ev []Evidence
+outer:
+	for _, f := range strings.Split(s, ";") {
+		for e, n := range evidenceTypes {
+			if n == f {
+				ev = append(ev, Evidence(e))
+				continue outer
+			}
+		}
+		return nil, fmt.Errorf("contig: unknown linkage evidence %q", f)
+	}
+	return ev, nil
+}
+
+// A Gap is a region of a Contig that is explicitly marked as lacking sequence.
+// Gap positions are rendered using the Contig's ground state letter.
+type Gap struct {
+	Start, End int
+
+	Type GapType
+
+	// Unknown specifies that the length of the gap is nominal
+	// and does not reflect the size of the missing sequence.
+	Unknown bool
+
+	// Linkage specifies whether there is evidence that the
+	// sequences on either side of the gap are linked.
+	Linkage  bool
+	Evidence []Evidence
+}
+
+// Len returns the length of the gap.
+func (g Gap) Len() int { return g.End - g.Start }
+
+// gapStep is a Contig step marked as a gap. The coordinates of the
+// held Gap are not used; gap coordinates are held by the step vector.
+type gapStep struct {
+	*Gap
+}
+
+func (g gapStep) Equal(e step.Equaler) bool {
+	o, ok := e.(gapStep)
+	return ok && g.Gap == o.Gap
+}
+
+func (g gapStep) String() string {
+	if g.Unknown {
+		return fmt.Sprintf("<%s gap?>", g.Type)
+	}
+	return fmt.Sprintf("<%s gap>", g.Type)
+}
+
+// InsertGap marks the region [g.Start, g.End) of the Contig as a gap. The region
+// must not contain any member sequence, but may replace all or part of an existing
+// gap. If the Contig is not relaxed a gap beyond the range of the Contig will
+// return an out of range error. Inserting a member over a gap replaces the gap at
+// the positions the member covers.
+func (c *Contig) InsertGap(g Gap) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if g.Start >= g.End {
+		return errors.New("contig: invalid gap range")
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	if !c.vector.Relaxed && (g.Start < c.Start() || g.End > c.End()) {
+		return errors.New("contig: gap out of range")
+	}
+	var overlap bool
+	c.do(max(g.Start, c.Start()), min(g.End, c.End()), func(_, _ int, e step.Equaler) {
+		if _, ok := e.(seqStep); ok {
+			overlap = true
+		}
+	})
+	if overlap {
+		return errors.New("contig: gap overlaps member")
+	}
+	g.Start, g.End = c.toBase(g.Start, g.End)
+	c.insertGap(g)
+	return nil
+}
+
+// insertGap marks g, given in base coordinates, as a gap.
+func (c *Contig) insertGap(g Gap) {
+	g.Evidence = append([]Evidence(nil), g.Evidence...)
+	c.vector.SetRange(g.Start, g.End, gapStep{&g})
+}
+
+// RemoveGap returns the gap covering position i of the Contig to the Contig's
+// ground state. Positions outside a circular Contig are wrapped onto it.
+func (c *Contig) RemoveGap(i int) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	start, end, e, err := c.vector.StepAt(c.base(c.normalize(i)))
+	if err != nil {
+		return err
+	}
+	if _, ok := e.(gapStep); !ok {
+		return errors.New("contig: no gap at specified position")
+	}
+	c.vector.SetRange(start, end, c.vector.Zero)
+	return nil
+}
+
+// Gaps returns the gaps of the Contig in order of position.
+func (c *Contig) Gaps() []Gap {
+	if c.vector == nil {
+		return nil
+	}
+	var gaps []Gap
+	c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+		if g, ok := e.(gapStep); ok {
+			gaps = append(gaps, g.at(start, end))
+		}
+	})
+	return gaps
+}
+
+// baseGaps returns the gaps of the Contig in base coordinates.
+func (c *Contig) baseGaps() []Gap {
+	var gaps []Gap
+	c.vector.Do(func(start, end int, e step.Equaler) {
+		if g, ok := e.(gapStep); ok {
+			gaps = append(gaps, g.at(start, end))
+		}
+	})
+	return gaps
+}
+
+// GapAt returns the gap covering position i of the Contig. If there is no gap at i,
+// ok is returned false. Positions outside a circular Contig are wrapped onto it.
+func (c *Contig) GapAt(i int) (g Gap, ok bool) {
+	if c.vector == nil {
+		return Gap{}, false
+	}
+	start, end, e, err := c.vector.StepAt(c.base(c.normalize(i)))
+	if err != nil {
+		return Gap{}, false
+	}
+	gs, ok := e.(gapStep)
+	if !ok {
+		return Gap{}, false
+	}
+	return gs.at(c.toView(start, end)), true
+}
+
+// at returns a copy of the gap placed at [start, end).
+func (g gapStep) at(start, end int) Gap {
+	gap := *g.Gap
+	gap.Start, gap.End = start, end
+	gap.Evidence = append([]Evidence(nil), gap.Evidence...)
+	return gap
+}
+
+func max(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,629 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
+	"github.com/biogo/biogo/seq/linear"
+)
+
+// GFAWriter writes Contig layouts in GFA 1 or GFA 2 format. Each member of
+// a Contig is written as a segment, overlaps between consecutive members are
+// written as links with a CIGAR alignment, members wholly contained by another
+// member are written as containments, and the separation between consecutive
+// members is written as a jump (GFA 1) or gap (GFA 2). The order of members is
+// written as a path (GFA 1) or ordered group (GFA 2) named with the Contig's ID.
+type GFAWriter struct {
+	w       io.Writer
+	version int
+	header  bool
+	edges   int
+}
+
+// NewGFAWriter returns a new GFAWriter that writes GFA of the given major version
+// to w. Version must be 1 or 2.
+func NewGFAWriter(w io.Writer, version int) (*GFAWriter, error) {
+	if version != 1 && version != 2 {
+		return nil, errors.New("contig: unsupported GFA version")
+	}
+	return &GFAWriter{w: w, version: version}, nil
+}
+
+// gfaRef is a placed reference to a segment. Start and end are the positions
+// of the segment in the Contig.
+type gfaRef struct {
+	name       string
+	start, end int
+	letters    []byte
+	rev        bool
+}
+
+func (r gfaRef) orient() byte { return orn(r.rev) }
+
+func flipOrient(o byte) byte {
+	if o == '-' {
+		return '+'
+	}
+	return '-'
+}
+
+// segmentRef returns the segment reference for the member p of c. If the member
+// sequence is a feat.Orienter in the reverse orientation, the segment holds the
+// reverse complement of the member's letters, otherwise the segment holds the
+// member's letters. The reference is reversed if the segment is complemented
+// relative to the Contig.
+func (c *Contig) segmentRef(p *placement) gfaRef {
+	var (
+		s   = p.s
+		rev = c.frameOf(p).complemented
+	)
+	if o, ok := s.(feat.Orienter); ok && o.Orientation() == feat.Reverse {
+		s = s.Clone()
+		s.RevComp()
+		rev = !rev
+	}
+	b := make([]byte, 0, s.Len())
+	for i := s.Start(); i < s.End(); i++ {
+		b = append(b, byte(s.At(i).L))
+	}
+	start, end := c.span(p)
+	return gfaRef{name: p.s.Name(), start: start, end: end, letters: b, rev: rev}
+}
+
+// Write writes the layout of c.
+func (w *GFAWriter) Write(c *Contig) error {
+	var err error
+	if !w.header {
+		if w.version == 1 {
+			_, err = fmt.Fprintln(w.w, "H\tVN:Z:1.2")
+		} else {
+			_, err = fmt.Fprintln(w.w, "H\tVN:Z:2.0")
+		}
+		if err != nil {
+			return err
+		}
+		w.header = true
+	}
+
+	refs := make([]gfaRef, len(c.members))
+	for i, p := range c.members {
+		refs[i] = c.segmentRef(p)
+	}
+	sort.Stable(byRefStart(refs))
+	for _, r := range refs {
+		if w.version == 1 {
+			_, err = fmt.Fprintf(w.w, "S\t%s\t%s\tLN:i:%d\n", r.name, r.letters, len(r.letters))
+		} else {
+			_, err = fmt.Fprintf(w.w, "S\t%s\t%d\t%s\n", r.name, len(r.letters), r.letters)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	var path []gfaRef
+	for _, r := range refs {
+		n := len(path)
+		if n != 0 && r.end <= path[n-1].end {
+			err = w.contain(path[n-1], r)
+			if err != nil {
+				return err
+			}
+			continue
+		}
+		if n != 0 {
+			err = w.join(c, path[n-1], r)
+			if err != nil {
+				return err
+			}
+		}
+		path = append(path, r)
+	}
+	if len(path) == 0 {
+		return nil
+	}
+
+	var b strings.Builder
+	for i, r := range path {
+		if i != 0 {
+			switch {
+			case w.version == 2:
+				b.WriteByte(' ')
+			case r.start > path[i-1].end:
+				b.WriteByte(';')
+			default:
+				b.WriteByte(',')
+			}
+		}
+		fmt.Fprintf(&b, "%s%c", r.name, r.orient())
+	}
+	if w.version == 1 {
+		_, err = fmt.Fprintf(w.w, "P\t%s\t%s\t*\n", c.ID, b.String())
+	} else {
+		_, err = fmt.Fprintf(w.w, "O\t%s\t%s\n", c.ID, b.String())
+	}
+	return err
+}
+
+// join writes the link, jump or gap between consecutive path members a and b.
+func (w *GFAWriter) join(c *Contig, a, b gfaRef) error {
+	var err error
+	d := b.start - a.end
+	switch {
+	case d > 0:
+		g, ok := c.GapAt(a.end)
+		if w.version == 1 {
+			dist := strconv.Itoa(d)
+			if ok && g.Unknown && g.End == b.start {
+				dist = "*"
+			}
+			_, err = fmt.Fprintf(w.w, "J\t%s\t%c\t%s\t%c\t%s\n", a.name, a.orient(), b.name, b.orient(), dist)
+		} else {
+			w.edges++
+			_, err = fmt.Fprintf(w.w, "G\tg%d\t%s%c\t%s%c\t%d\t*\n", w.edges, a.name, a.orient(), b.name, b.orient(), d)
+		}
+	case w.version == 1:
+		_, err = fmt.Fprintf(w.w, "L\t%s\t%c\t%s\t%c\t%dM\n", a.name, a.orient(), b.name, b.orient(), -d)
+	default:
+		n := -d
+		la, lb := len(a.letters), len(b.letters)
+		ab, ae := gfaPos(la-n, la), gfaPos(la, la)
+		if a.rev {
+			ab, ae = gfaPos(0, la), gfaPos(n, la)
+		}
+		bb, be := gfaPos(0, lb), gfaPos(n, lb)
+		if b.rev {
+			bb, be = gfaPos(lb-n, lb), gfaPos(lb, lb)
+		}
+		w.edges++
+		_, err = fmt.Fprintf(w.w, "E\te%d\t%s%c\t%s%c\t%s\t%s\t%s\t%s\t%dM\n",
+			w.edges, a.name, a.orient(), b.name, b.orient(), ab, ae, bb, be, n)
+	}
+	return err
+}
+
+// contain writes the containment of b by a.
+func (w *GFAWriter) contain(a, b gfaRef) error {
+	var (
+		la  = len(a.letters)
+		pos = b.start - a.start
+		o   = byte('+')
+	)
+	if a.rev {
+		pos = a.end - b.end
+	}
+	if a.rev != b.rev {
+		o = '-'
+	}
+	if w.version == 1 {
+		_, err := fmt.Fprintf(w.w, "C\t%s\t+\t%s\t%c\t%d\t%dM\n", a.name, b.name, o, pos, len(b.letters))
+		return err
+	}
+	w.edges++
+	_, err := fmt.Fprintf(w.w, "E\te%d\t%s+\t%s%c\t%s\t%s\t%s\t%s\t%dM\n",
+		w.edges, a.name, b.name, o,
+		gfaPos(pos, la), gfaPos(pos+len(b.letters), la), gfaPos(0, len(b.letters)), gfaPos(len(b.letters), len(b.letters)),
+		len(b.letters))
+	return err
+}
+
+// gfaPos returns the GFA 2 representation of position p on a segment of length n.
+func gfaPos(p, n int) string {
+	if p == n {
+		return strconv.Itoa(p) + "$"
+	}
+	return strconv.Itoa(p)
+}
+
+type byRefStart []gfaRef
+
+func (r byRefStart) Len() int           { return len(r) }
+func (r byRefStart) Less(i, j int) bool { return r[i].start < r[j].start }
+func (r byRefStart) Swap(i, j int)      { r[i], r[j] = r[j], r[i] }
+
+// gfaKey identifies an oriented pair of segments.
+type gfaKey struct {
+	from, to       string
+	fromOrn, toOrn byte
+}
+
+// gfaJoin is the relationship between an oriented pair of segments. Overlaps
+// are represented by negative distances.
+type gfaJoin struct {
+	dist    int
+	unknown bool
+}
+
+// gfaContain is a containment of a segment within a container segment.
+type gfaContain struct {
+	container string
+	contained string
+	rev       bool // The contained segment is reversed relative to the container.
+	pos       int
+}
+
+// gfaPath is a named ordered list of oriented segments.
+type gfaPath struct {
+	name string
+	segs []string
+	rev  []bool
+}
+
+// ReadGFA reads a GFA 1 or GFA 2 document from r and returns a Contig for each
+// path (GFA 1) or ordered group (GFA 2). Segments are placed in path order with
+// each segment offset from the end of its predecessor by the overlap of the link
+// or edge between them, or by the distance of the jump or gap between them, or
+// abutting if neither is present. Segments contained by a path segment are placed
+// at their containment position. Jumps and gaps are added to the Contig as Gaps.
+// The version of the document is taken from its header, defaulting to GFA 1.
+// Each visit of a path to a segment is placed as a member; the first is named
+// with the segment's name and later visits with the name followed by "#" and the
+// visit number, so the third visit to segment "a" is named "a#3". Segments
+// without a sequence, given as "*", are not supported.
+func ReadGFA(r io.Reader, alpha alphabet.Alphabet) ([]*Contig, error) {
+	var (
+		version  = 1
+		segs     = make(map[string][]byte)
+		joins    = make(map[gfaKey]gfaJoin)
+		contains []gfaContain
+		paths    []gfaPath
+	)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, 1<<30)
+	for n := 1; sc.Scan(); n++ {
+		f := strings.Split(sc.Text(), "\t")
+		if len(f) == 0 || f[0] == "" || f[0] == "#" {
+			continue
+		}
+		var err error
+		switch f[0] {
+		case "H":
+			for _, t := range f[1:] {
+				if strings.HasPrefix(t, "VN:Z:2") {
+					version = 2
+				}
+			}
+		case "S":
+			err = gfaFields(f, 3, n)
+			if err != nil {
+				return nil, err
+			}
+			sf := f[2]
+			if version != 1 {
+				err = gfaFields(f, 4, n)
+				if err != nil {
+					return nil, err
+				}
+				sf = f[3]
+			}
+			if sf == "*" {
+				err = fmt.Errorf("no sequence for segment %q", f[1])
+				break
+			}
+			segs[f[1]] = []byte(sf)
+		case "L", "J":
+			err = gfaFields(f, 6, n)
+			if err != nil {
+				return nil, err
+			}
+			var ao, bo byte
+			ao, err = gfaOrient(f[2])
+			if err != nil {
+				break
+			}
+			bo, err = gfaOrient(f[4])
+			if err != nil {
+				break
+			}
+			var j gfaJoin
+			if f[0] == "L" {
+				j.dist, err = cigarLen(f[5])
+				j.dist = -j.dist
+			} else if f[5] == "*" {
+				j.unknown = true
+				j.dist = DefaultUnknownGap
+			} else {
+				j.dist, err = strconv.Atoi(f[5])
+			}
+			addJoin(joins, f[1], ao, f[3], bo, j)
+		case "C":
+			err = gfaFields(f, 7, n)
+			if err != nil {
+				return nil, err
+			}
+			var ao, bo byte
+			ao, err = gfaOrient(f[2])
+			if err != nil {
+				break
+			}
+			bo, err = gfaOrient(f[4])
+			if err != nil {
+				break
+			}
+			var pos int
+			pos, err = strconv.Atoi(f[5])
+			contains = append(contains, gfaContain{
+				container: f[1],
+				contained: f[3],
+				rev:       ao != bo,
+				pos:       pos,
+			})
+		case "P":
+			err = gfaFields(f, 3, n)
+			if err != nil {
+				return nil, err
+			}
+			var p gfaPath
+			p, err = gfaSegRefs(f[1], strings.FieldsFunc(f[2], func(r rune) bool { return r == ',' || r == ';' }))
+			paths = append(paths, p)
+		case "E":
+			err = gfaFields(f, 9, n)
+			if err != nil {
+				return nil, err
+			}
+			err = gfaEdge(f, segs, joins, &contains)
+		case "G":
+			err = gfaFields(f, 6, n)
+			if err != nil {
+				return nil, err
+			}
+			var (
+				a, b   string
+				ao, bo byte
+				d      int
+			)
+			a, ao, err = gfaSegRef(f[2])
+			if err != nil {
+				break
+			}
+			b, bo, err = gfaSegRef(f[3])
+			if err != nil {
+				break
+			}
+			d, err = strconv.Atoi(f[4])
+			addJoin(joins, a, ao, b, bo, gfaJoin{dist: d})
+		case "O":
+			err = gfaFields(f, 3, n)
+			if err != nil {
+				return nil, err
+			}
+			var p gfaPath
+			p, err = gfaSegRefs(f[1], strings.Fields(f[2]))
+			paths = append(paths, p)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("contig: gfa line %d: %v", n, err)
+		}
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+
+	var cons []*Contig
+	for _, p := range paths {
+		c, err := gfaContig(p, segs, joins, contains, alpha)
+		if err != nil {
+			return nil, err
+		}
+		cons = append(cons, c)
+	}
+	return cons, nil
+}
+
+func gfaFields(f []string, n, line int) error {
+	if len(f) < n {
+		return fmt.Errorf("contig: gfa line %d: too few fields", line)
+	}
+	return nil
+}
+
+// gfaOrient returns the orientation held in the GFA orientation field s.
+func gfaOrient(s string) (byte, error) {
+	if s != "+" && s != "-" {
+		return 0, fmt.Errorf("invalid orientation %q", s)
+	}
+	return s[0], nil
+}
+
+// gfaSegRef returns the segment name and orientation of the oriented segment
+// reference s.
+func gfaSegRef(s string) (name string, orient byte, err error) {
+	if len(s) < 2 {
+		return "", 0, fmt.Errorf("invalid segment reference %q", s)
+	}
+	orient, err = gfaOrient(s[len(s)-1:])
+	if err != nil {
+		return "", 0, fmt.Errorf("invalid segment reference %q", s)
+	}
+	return s[:len(s)-1], orient, nil
+}
+
+// gfaSegRefs returns the path named name through the oriented segment
+// references in refs.
+func gfaSegRefs(name string, refs []string) (gfaPath, error) {
+	if len(refs) == 0 {
+		return gfaPath{}, fmt.Errorf("no segments in path %q", name)
+	}
+	p := gfaPath{name: name}
+	for _, r := range refs {
+		seg, o, err := gfaSegRef(r)
+		if err != nil {
+			return gfaPath{}, err
+		}
+		p.segs = append(p.segs, seg)
+		p.rev = append(p.rev, o == '-')
+	}
+	return p, nil
+}
+
+// addJoin records j for the oriented pair and for its reverse complement.
+func addJoin(joins map[gfaKey]gfaJoin, from string, fromOrn byte, to string, toOrn byte, j gfaJoin) {
+	joins[gfaKey{from: from, fromOrn: fromOrn, to: to, toOrn: toOrn}] = j
+	joins[gfaKey{from: to, fromOrn: flipOrient(toOrn), to: from, toOrn: flipOrient(fromOrn)}] = j
+}
+
+// gfaEdge records a GFA 2 edge as either a join or a containment.
+func gfaEdge(f []string, segs map[string][]byte, joins map[gfaKey]gfaJoin, contains *[]gfaContain) error {
+	an, ao, err := gfaSegRef(f[2])
+	if err != nil {
+		return err
+	}
+	bn, bo, err := gfaSegRef(f[3])
+	if err != nil {
+		return err
+	}
+	var p [4]int
+	for i, s := range f[4:8] {
+		p[i], err = strconv.Atoi(strings.TrimSuffix(s, "$"))
+		if err != nil {
+			return err
+		}
+	}
+	la, lb := len(segs[an]), len(segs[bn])
+	switch {
+	case p[2] == 0 && p[3] == lb:
+		*contains = append(*contains, gfaContain{container: an, contained: bn, rev: ao != bo, pos: p[0]})
+	case p[0] == 0 && p[1] == la:
+		*contains = append(*contains, gfaContain{container: bn, contained: an, rev: ao != bo, pos: p[2]})
+	default:
+		addJoin(joins, an, ao, bn, bo, gfaJoin{dist: -(p[1] - p[0])})
+	}
+	return nil
+}
+
+// cigarLen returns the length of the reference consumed by the CIGAR string s.
+func cigarLen(s string) (int, error) {
+	if s == "*" {
+		return 0, nil
+	}
+	var n, l int
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if '0' <= c && c <= '9' {
+			l = l*10 + int(c-'0')
+			continue
+		}
+		switch c {
+		case 'M', '=', 'X', 'D', 'N':
+			n += l
+		case 'I', 'S', 'H', 'P':
+		default:
+			return 0, fmt.Errorf("invalid CIGAR operation %q", c)
+		}
+		l = 0
+	}
+	return n, nil
+}
+
+// gfaPlaced is a segment placed in a Contig under construction.
+type gfaPlaced struct {
+	name       string
+	start, end int
+	rev        bool
+}
+
+// gfaContig builds the Contig described by the path p.
+func gfaContig(p gfaPath, segs map[string][]byte, joins map[gfaKey]gfaJoin, contains []gfaContain, alpha alphabet.Alphabet) (*Contig, error) {
+	var (
+		placed []gfaPlaced
+		gaps   []Gap
+		end    int
+	)
+	for i, name := range p.segs {
+		s, ok := segs[name]
+		if !ok {
+			return nil, fmt.Errorf("contig: gfa: no segment %q", name)
+		}
+		start := end
+		if i != 0 {
+			prev := placed[len(placed)-1]
+			j := joins[gfaKey{from: prev.name, fromOrn: orn(prev.rev), to: name, toOrn: orn(p.rev[i])}]
+			start = prev.end + j.dist
+			if j.dist > 0 {
+				gaps = append(gaps, Gap{
+					Start:    prev.end,
+					End:      start,
+					Type:     ScaffoldGap,
+					Unknown:  j.unknown,
+					Linkage:  true,
+					Evidence: []Evidence{Unspecified},
+				})
+			}
+		}
+		placed = append(placed, gfaPlaced{name: name, start: start, end: start + len(s), rev: p.rev[i]})
+		end = start + len(s)
+	}
+	for _, ct := range contains {
+		for _, cp := range placed {
+			if cp.name != ct.container {
+				continue
+			}
+			l := len(segs[ct.contained])
+			start := cp.start + ct.pos
+			if cp.rev {
+				start = cp.end - ct.pos - l
+			}
+			placed = append(placed, gfaPlaced{name: ct.contained, start: start, end: start + l, rev: cp.rev != ct.rev})
+			break
+		}
+	}
+
+	var lo, hi int
+	for _, pl := range placed {
+		if pl.start < lo {
+			lo = pl.start
+		}
+		if pl.end > hi {
+			hi = pl.end
+		}
+	}
+	c, err := New(p.name, hi, alpha)
+	if err != nil {
+		return nil, err
+	}
+	c.Relaxed(lo < 0)
+	for _, g := range gaps {
+		err = c.InsertGap(g)
+		if err != nil {
+			return nil, err
+		}
+	}
+	visits := make(map[string]int)
+	for _, pl := range placed {
+		// Later visits to a segment are named
+		// with the segment's visit number.
+		id := pl.name
+		visits[pl.name]++
+		if n := visits[pl.name]; n > 1 {
+			id = fmt.Sprintf("%s#%d", pl.name, n)
+		}
+		m := linear.NewSeq(id, alphabet.BytesToLetters(segs[pl.name]), alpha)
+		if pl.rev {
+			m.RevComp()
+		}
+		m.SetOffset(pl.start)
+		err = c.Insert(m)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func orn(rev bool) byte {
+	if rev {
+		return '-'
+	}
+	return '+'
+}
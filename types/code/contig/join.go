@@ -0,0 +1,212 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/store/step"
+)
+
+// A Part is a Contig placed in a scaffold constructed by Join.
+type Part struct {
+	*Contig
+
+	// Strand specifies the orientation of the Contig in
+	// the scaffold. If Strand is seq.Minus the reverse
+	// complement of the Contig is placed.
+	Strand seq.Strand
+
+	// Gap is the gap preceding the Contig in the scaffold.
+	// Its length is given by Gap.Len; Gap.Start and Gap.End
+	// are otherwise ignored. A zero length Gap places the
+	// Contig immediately after the preceding part. Gap is
+	// ignored for the first part.
+	Gap Gap
+}
+
+// Join returns a new Contig with the given ID composed of the parts placed end
+// to end in order and separated by their gaps. The members of each part are
+// placed in the returned Contig with their orientation relative to the part
+// retained, so member sequences, gaps and features are shared rather than
+// copied. The returned Contig has the alphabet, ground state and policy of the
+// first part. All parts must have the same alphabet and member IDs must be
+// unique across the parts. Parts are placed as described by Merge, so members
+// of a circular part must not span its origin.
+func Join(id string, parts ...Part) (*Contig, error) {
+	if len(parts) == 0 {
+		return nil, errors.New("contig: no parts to join")
+	}
+	var n int
+	for i, p := range parts {
+		if i != 0 {
+			if p.Gap.Len() < 0 {
+				return nil, errors.New("contig: invalid gap range")
+			}
+			n += p.Gap.Len()
+		}
+		n += p.Len()
+	}
+	first := parts[0].Contig
+	zero := step.Equaler(ambig(first.Alpha.Ambiguous()))
+	if first.vector != nil {
+		zero = first.vector.Zero
+	}
+	v, err := step.New(0, n, zero)
+	if err != nil {
+		return nil, err
+	}
+	c := &Contig{
+		Annotation: &seq.Annotation{ID: id, Alpha: first.Alpha},
+		policy:     first.policy,
+		groundQ:    first.groundQ,
+		encode:     first.encode,
+		masking:    first.masking,
+		index:      make(map[string]*placement),
+		vector:     v,
+	}
+	var at int
+	for i, p := range parts {
+		if i != 0 && p.Gap.Len() != 0 {
+			g := p.Gap
+			g.Start, g.End = at, at+p.Gap.Len()
+			c.insertGap(g)
+			at = g.End
+		}
+		err = c.Merge(p.Contig, at, p.Strand)
+		if err != nil {
+			return nil, err
+		}
+		at += p.Len()
+	}
+	return c, nil
+}
+
+// Merge places the members, gaps and features of src into the Contig with the
+// start of src at position at. If strand is seq.Minus the reverse complement of
+// src is placed. Members of src are inserted in their insertion order with their
+// orientation relative to src retained, and member sequences are shared with src
+// unless src is a snapshot, in which case they are cloned. Gaps of src are only
+// marked at positions not covered by a member and masks of src are added to those
+// of the Contig. The alphabet of src must match the Contig's alphabet and the IDs
+// of its members must not match those of the Contig's members. If src is circular,
+// none of its members may span its origin; such a Contig may be rotated so that
+// they do not before merging. If the Contig is not relaxed, src must lie within
+// the Contig. If the Contig is strict and a member of src disagrees with an
+// existing member, the Contig is not altered and a *ConflictError is returned.
+func (c *Contig) Merge(src *Contig, at int, strand seq.Strand) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	if src.Alphabet() != c.Alphabet() {
+		return errors.New("contig: alphabet mismatch")
+	}
+	if c.vector == nil {
+		return errEmpty
+	}
+	if c.IsCircular() {
+		if src.Len() > c.Len() {
+			return errors.New("contig: sequence longer than circular contig")
+		}
+	} else if !c.vector.Relaxed && (at < c.Start() || at+src.Len() > c.End()) {
+		return errors.New("contig: sequence out of range")
+	}
+	if src.vector == nil {
+		return nil
+	}
+
+	var f frame
+	if strand == seq.Minus {
+		f = frame{reversed: true, complemented: true}
+	}
+	// pos returns the position in c of the interval [s, e) of src.
+	pos := func(s, e int) (int, int) {
+		if f.reversed {
+			return at + src.End() - e, at + src.End() - s
+		}
+		return at + s - src.Start(), at + e - src.Start()
+	}
+
+	placed := make(map[*placement]*placement, len(src.members))
+	for _, p := range src.members {
+		if _, ok := c.index[p.s.Name()]; ok {
+			return errors.New("contig: duplicate member ID")
+		}
+		if len(src.extents(p)) != 1 {
+			return fmt.Errorf("contig: member %q spans the origin of %q", p.s.Name(), src.ID)
+		}
+		start, _ := pos(src.span(p))
+		s := p.s
+		if src.frozen {
+			// Sequences of a snapshot must not be shared
+			// with a Contig that may alter them with Set.
+			s = s.Clone()
+		}
+		placed[p] = c.newPlacement(s, start, src.frameOf(p).compose(f))
+	}
+	if c.strict {
+		var conflicts []Conflict
+		for _, p := range src.members {
+			conflicts = append(conflicts, c.conflicts(placed[p], func(*placement) bool { return true })...)
+		}
+		if len(conflicts) != 0 {
+			return &ConflictError{ID: src.ID, Conflicts: conflicts}
+		}
+	}
+
+	for _, g := range src.Gaps() {
+		g.Start, g.End = pos(g.Start, g.End)
+		c.markGap(g)
+	}
+	for _, r := range src.Masked() {
+		c.mask(pos(r.Start, r.End))
+	}
+	for _, p := range src.members {
+		err := c.insert(placed[p])
+		if err != nil {
+			return err
+		}
+	}
+	for _, a := range src.features {
+		if a.anchor != nil {
+			ac := *a
+			ac.anchor = placed[a.anchor]
+			c.features = append(c.features, &ac)
+			continue
+		}
+		pl := src.place(a)
+		start, end := pos(pl.Start, pl.End)
+		o := pl.Orientation
+		if f.reversed {
+			o = -o
+		}
+		c.attach(a.f, start, end, o)
+	}
+	return nil
+}
+
+// markGap marks the positions of g, given in the Contig's current coordinates,
+// that are not covered by a member as a gap.
+func (c *Contig) markGap(g Gap) {
+	g.Evidence = append([]Evidence(nil), g.Evidence...)
+	gs := gapStep{&g}
+	start, end := c.toBase(g.Start, g.End)
+	wrap := 0
+	if c.IsCircular() {
+		wrap = c.Len()
+		start = c.vector.Start() + mod(start-c.vector.Start(), wrap)
+		end = start + g.Len()
+	}
+	for _, r := range c.ranges(start, end, wrap) {
+		c.vector.ApplyRange(r[0], r[1], func(e step.Equaler) step.Equaler {
+			if _, ok := e.(seqStep); ok {
+				return e
+			}
+			return gs
+		})
+	}
+}
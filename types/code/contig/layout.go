@@ -0,0 +1,451 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/gob"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/feat"
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/biogo/seq/linear"
+)
+
+// LayoutVersion is the version of the layout encoding written by WriteLayout
+// and WriteLayoutJSON. ReadLayout reads layouts of this and earlier versions.
+// Version 1 layouts do not hold the strand of a Contig, which is read as
+// seq.None.
+const LayoutVersion = 2
+
+const (
+	// layoutMagic begins a binary layout.
+	layoutMagic = "BGCL"
+
+	// layoutFormat identifies a JSON layout.
+	layoutFormat = "biogo-contig-layout"
+)
+
+// A SeqStorage specifies how member sequences are held by an encoded layout.
+type SeqStorage int
+
+const (
+	InlineSeqs     SeqStorage = iota // Member letters and qualities are held in the layout.
+	ReferencedSeqs                   // Members refer by ID to sequences held elsewhere.
+)
+
+// layoutAlphabets are the alphabets that may be named in a layout.
+var layoutAlphabets = []struct {
+	name  string
+	alpha alphabet.Alphabet
+}{
+	{"DNA", alphabet.DNA},
+	{"RNA", alphabet.RNA},
+	{"Protein", alphabet.Protein},
+}
+
+// layoutFile is the encoded form of a set of Contig layouts.
+type layoutFile struct {
+	Format  string         `json:"format"`
+	Version int            `json:"version"`
+	Contigs []layoutContig `json:"contigs"`
+}
+
+// layoutContig is the encoded form of a Contig layout in its current orientation.
+type layoutContig struct {
+	ID       string `json:"id"`
+	Desc     string `json:"desc,omitempty"`
+	Alphabet string `json:"alphabet"`
+	Strand   int    `json:"strand"`
+	Start    int    `json:"start"`
+	Len      int    `json:"len"`
+	Circular bool   `json:"circular,omitempty"`
+	Relaxed  bool   `json:"relaxed,omitempty"`
+	Strict   bool   `json:"strict,omitempty"`
+	Policy   string `json:"policy"`
+	Joiner   string `json:"joiner"`
+	GroundQ  int    `json:"ground_quality"`
+	Encoding int    `json:"encoding"`
+	Masking  string `json:"masking"`
+
+	Masks   [][2]int       `json:"masks,omitempty"`
+	Gaps    []layoutGap    `json:"gaps,omitempty"`
+	Members []layoutMember `json:"members,omitempty"`
+}
+
+type layoutGap struct {
+	Start    int      `json:"start"`
+	End      int      `json:"end"`
+	Type     string   `json:"type"`
+	Unknown  bool     `json:"unknown,omitempty"`
+	Linkage  bool     `json:"linkage,omitempty"`
+	Evidence []string `json:"evidence,omitempty"`
+}
+
+// layoutMember is the encoded form of a member. Start, Reversed and Complemented
+// give the placement of the member in the Contig, and ID, Desc, Offset and Strand
+// the annotation of the member sequence. The letters of an inline member are held
+// by Letters and Quality, and those of a referenced member are the interval
+// [From, To) of the sequence named Source, reverse complemented if RevComp is true.
+type layoutMember struct {
+	ID           string `json:"id"`
+	Desc         string `json:"desc,omitempty"`
+	Offset       int    `json:"offset"`
+	Strand       int    `json:"strand"`
+	Start        int    `json:"start"`
+	Reversed     bool   `json:"reversed,omitempty"`
+	Complemented bool   `json:"complemented,omitempty"`
+
+	Component *Component `json:"component,omitempty"`
+
+	Packed   bool   `json:"packed,omitempty"`
+	Letters  string `json:"letters,omitempty"`
+	Quality  []byte `json:"quality,omitempty"`
+	Encoding int    `json:"encoding,omitempty"`
+
+	Source  string `json:"source,omitempty"`
+	From    int    `json:"from,omitempty"`
+	To      int    `json:"to,omitempty"`
+	RevComp bool   `json:"revcomp,omitempty"`
+}
+
+// WriteLayout writes the layouts of the Contigs in their current orientations to
+// w in the binary layout encoding. The encoding holds the ID, description,
+// alphabet, strand, range, conformation, relaxation, strictness, policy, joiner,
+// quality settings, masking, masks, gaps and members of each Contig, with members
+// in insertion order. Member sequences are held according to storage; a referenced
+// member refers to its Component's sequence if its Location is a Component, and
+// otherwise to a sequence with the member's ID holding the member's letters.
+// Features are not encoded, so an error is returned if a Contig has features.
+// Only the DNA, RNA and Protein alphabets are supported.
+func WriteLayout(w io.Writer, storage SeqStorage, cons ...*Contig) error {
+	f, err := newLayoutFile(storage, cons)
+	if err != nil {
+		return err
+	}
+	var b [len(layoutMagic) + 4]byte
+	copy(b[:], layoutMagic)
+	binary.LittleEndian.PutUint32(b[len(layoutMagic):], LayoutVersion)
+	_, err = w.Write(b[:])
+	if err != nil {
+		return err
+	}
+	return gob.NewEncoder(w).Encode(f)
+}
+
+// WriteLayoutJSON writes the layouts of the Contigs to w as JSON. The JSON
+// layout encoding holds the same information as the binary encoding written
+// by WriteLayout.
+func WriteLayoutJSON(w io.Writer, storage SeqStorage, cons ...*Contig) error {
+	f, err := newLayoutFile(storage, cons)
+	if err != nil {
+		return err
+	}
+	return json.NewEncoder(w).Encode(f)
+}
+
+// ReadLayout reads Contig layouts written by WriteLayout or WriteLayoutJSON
+// from r, detecting the encoding used. Referenced member sequences are obtained
+// from seqs, which may be nil if all members are held inline.
+func ReadLayout(r io.Reader, seqs map[string]seq.Sequence) ([]*Contig, error) {
+	br := bufio.NewReader(r)
+	head, err := br.Peek(len(layoutMagic) + 4)
+	if err != nil && len(head) == 0 {
+		return nil, err
+	}
+	var f layoutFile
+	if bytes.HasPrefix(head, []byte(layoutMagic)) {
+		if len(head) < len(layoutMagic)+4 {
+			return nil, io.ErrUnexpectedEOF
+		}
+		f.Version = int(binary.LittleEndian.Uint32(head[len(layoutMagic):]))
+		if f.Version < 1 || f.Version > LayoutVersion {
+			return nil, fmt.Errorf("contig: layout: unsupported version %d", f.Version)
+		}
+		br.Discard(len(head))
+		err = gob.NewDecoder(br).Decode(&f)
+	} else {
+		err = json.NewDecoder(br).Decode(&f)
+		if err == nil && f.Format != layoutFormat {
+			return nil, errors.New("contig: layout: invalid format")
+		}
+	}
+	if err != nil {
+		return nil, fmt.Errorf("contig: layout: %v", err)
+	}
+	if f.Version < 1 || f.Version > LayoutVersion {
+		return nil, fmt.Errorf("contig: layout: unsupported version %d", f.Version)
+	}
+
+	cons := make([]*Contig, len(f.Contigs))
+	for i, lc := range f.Contigs {
+		cons[i], err = lc.contig(f.Version, seqs)
+		if err != nil {
+			return nil, fmt.Errorf("contig: layout: %q: %v", lc.ID, err)
+		}
+	}
+	return cons, nil
+}
+
+func newLayoutFile(storage SeqStorage, cons []*Contig) (layoutFile, error) {
+	f := layoutFile{Format: layoutFormat, Version: LayoutVersion, Contigs: make([]layoutContig, len(cons))}
+	for i, c := range cons {
+		var err error
+		f.Contigs[i], err = c.encodeLayout(storage)
+		if err != nil {
+			return f, err
+		}
+	}
+	return f, nil
+}
+
+// encodeLayout returns the encoded layout of the Contig.
+func (c *Contig) encodeLayout(storage SeqStorage) (layoutContig, error) {
+	l := layoutContig{
+		ID:       c.ID,
+		Desc:     c.Desc,
+		Strand:   int(c.Strand),
+		Start:    c.Start(),
+		Len:      c.Len(),
+		Circular: c.IsCircular(),
+		Strict:   c.strict,
+		Policy:   c.policy.String(),
+		GroundQ:  int(c.groundQ),
+		Encoding: int(c.encode),
+		Masking:  c.masking.String(),
+	}
+	for _, a := range layoutAlphabets {
+		if a.alpha == c.Alpha {
+			l.Alphabet = a.name
+		}
+	}
+	if l.Alphabet == "" {
+		return l, fmt.Errorf("contig: layout: unsupported alphabet for %q", c.ID)
+	}
+	if len(c.features) != 0 {
+		return l, fmt.Errorf("contig: layout: cannot encode features of %q", c.ID)
+	}
+	if c.vector == nil {
+		return l, nil
+	}
+	l.Relaxed = c.IsRelaxed()
+	l.Joiner = string(rune(c.Joiner()))
+	for _, r := range c.Masked() {
+		l.Masks = append(l.Masks, [2]int{r.Start, r.End})
+	}
+	for _, g := range c.Gaps() {
+		lg := layoutGap{Start: g.Start, End: g.End, Type: g.Type.String(), Unknown: g.Unknown, Linkage: g.Linkage}
+		for _, e := range g.Evidence {
+			lg.Evidence = append(lg.Evidence, e.String())
+		}
+		l.Gaps = append(l.Gaps, lg)
+	}
+	for _, p := range c.members {
+		start, _ := c.span(p)
+		f := c.frameOf(p)
+		s := p.s
+		m := layoutMember{
+			ID:           s.Name(),
+			Desc:         s.Description(),
+			Offset:       s.Start(),
+			Start:        start,
+			Reversed:     f.reversed,
+			Complemented: f.complemented,
+		}
+		if o, ok := s.(feat.Orienter); ok {
+			m.Strand = int(o.Orientation())
+		}
+		if cm, ok := s.Location().(Component); ok {
+			m.Component = &cm
+		}
+		switch storage {
+		case InlineSeqs:
+			_, m.Packed = s.(*Packed)
+			b := make([]byte, s.Len())
+			var q []byte
+			enc, ok := s.(encoder)
+			if ok {
+				q = make([]byte, s.Len())
+				m.Encoding = int(enc.Encoding())
+			}
+			for i := range b {
+				l := s.At(s.Start() + i)
+				b[i] = byte(l.L)
+				if q != nil {
+					q[i] = byte(l.Q)
+				}
+			}
+			m.Letters, m.Quality = string(b), q
+		case ReferencedSeqs:
+			m.Source, m.From, m.To = s.Name(), 0, s.Len()
+			if m.Component != nil {
+				m.Source, m.From, m.To = m.Component.ID, m.Component.From, m.Component.To
+				m.RevComp = seq.Strand(m.Strand) == seq.Minus
+			}
+		default:
+			return l, fmt.Errorf("contig: layout: invalid sequence storage %d", storage)
+		}
+		l.Members = append(l.Members, m)
+	}
+	return l, nil
+}
+
+// contig returns the Contig described by the layout of the given version.
+func (l layoutContig) contig(version int, seqs map[string]seq.Sequence) (*Contig, error) {
+	var alpha alphabet.Alphabet
+	for _, a := range layoutAlphabets {
+		if a.name == l.Alphabet {
+			alpha = a.alpha
+		}
+	}
+	if alpha == nil {
+		return nil, fmt.Errorf("unknown alphabet %q", l.Alphabet)
+	}
+	c, err := New(l.ID, l.Len, alpha)
+	if err != nil {
+		return nil, err
+	}
+	c.Desc = l.Desc
+	if version >= 2 {
+		c.Strand = seq.Strand(l.Strand)
+	}
+	c.SetOffset(l.Start)
+	if l.Circular {
+		c.SetConformation(feat.Circular)
+	}
+	c.Relaxed(l.Relaxed)
+	if l.Joiner != "" {
+		if len(l.Joiner) != 1 {
+			return nil, fmt.Errorf("invalid joiner %q", l.Joiner)
+		}
+		c.vector.Zero = ambig(l.Joiner[0])
+	}
+	c.groundQ = alphabet.Qphred(l.GroundQ)
+	c.encode = alphabet.Encoding(l.Encoding)
+	c.policy, err = parsePolicy(l.Policy)
+	if err != nil {
+		return nil, err
+	}
+	c.masking, err = parseMasking(l.Masking)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, lm := range l.Members {
+		if _, ok := c.index[lm.ID]; ok {
+			return nil, errors.New("duplicate member ID")
+		}
+		s, err := lm.seq(alpha, seqs)
+		if err != nil {
+			return nil, err
+		}
+		if !l.Relaxed && !l.Circular && (lm.Start < c.Start() || lm.Start+s.Len() > c.End()) {
+			return nil, fmt.Errorf("member %q out of range", lm.ID)
+		}
+		err = c.insert(c.newPlacement(s, lm.Start, frame{reversed: lm.Reversed, complemented: lm.Complemented}))
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, lg := range l.Gaps {
+		g := Gap{Start: lg.Start, End: lg.End, Unknown: lg.Unknown, Linkage: lg.Linkage}
+		g.Type, err = parseGapType(lg.Type)
+		if err != nil {
+			return nil, err
+		}
+		for _, s := range lg.Evidence {
+			ev, err := parseEvidence(s)
+			if err != nil {
+				return nil, err
+			}
+			g.Evidence = append(g.Evidence, ev...)
+		}
+		err = c.InsertGap(g)
+		if err != nil {
+			return nil, err
+		}
+	}
+	for _, r := range l.Masks {
+		err = c.Mask(r[0], r[1])
+		if err != nil {
+			return nil, err
+		}
+	}
+	c.strict = l.Strict
+	return c, nil
+}
+
+// seq returns the member sequence described by the layout.
+func (l layoutMember) seq(alpha alphabet.Alphabet, seqs map[string]seq.Sequence) (seq.Sequence, error) {
+	a := seq.Annotation{ID: l.ID, Desc: l.Desc, Strand: seq.Strand(l.Strand), Alpha: alpha, Offset: l.Offset}
+	if l.Component != nil {
+		a.Loc = *l.Component
+	}
+
+	var (
+		b  []alphabet.Letter
+		ql []alphabet.QLetter
+	)
+	switch {
+	case l.Source != "":
+		src, ok := seqs[l.Source]
+		if !ok {
+			return nil, fmt.Errorf("no sequence for %q", l.Source)
+		}
+		if l.From < 0 || l.To < l.From || l.To > src.Len() {
+			return nil, fmt.Errorf("invalid range for %q", l.Source)
+		}
+		if src.Alphabet() != alpha {
+			return nil, errors.New("alphabet mismatch")
+		}
+		var s seq.Sequence
+		if enc, ok := src.(encoder); ok {
+			for i := l.From; i < l.To; i++ {
+				ql = append(ql, src.At(src.Start()+i))
+			}
+			qs := linear.NewQSeq(l.ID, ql, alpha, enc.Encoding())
+			s = qs
+			if l.RevComp {
+				qs.RevComp()
+			}
+			qs.Annotation = a
+		} else {
+			for i := l.From; i < l.To; i++ {
+				b = append(b, src.At(src.Start()+i).L)
+			}
+			ls := linear.NewSeq(l.ID, b, alpha)
+			s = ls
+			if l.RevComp {
+				ls.RevComp()
+			}
+			ls.Annotation = a
+		}
+		return s, nil
+	case l.Packed:
+		p := NewPacked(l.ID, alphabet.BytesToLetters([]byte(l.Letters)), alpha)
+		p.Annotation = a
+		return p, nil
+	case l.Quality != nil:
+		if len(l.Quality) != len(l.Letters) {
+			return nil, fmt.Errorf("quality length mismatch for %q", l.ID)
+		}
+		ql = make([]alphabet.QLetter, len(l.Letters))
+		for i := range ql {
+			ql[i] = alphabet.QLetter{L: alphabet.Letter(l.Letters[i]), Q: alphabet.Qphred(l.Quality[i])}
+		}
+		qs := linear.NewQSeq(l.ID, ql, alpha, alphabet.Encoding(l.Encoding))
+		qs.Annotation = a
+		return qs, nil
+	}
+	ls := linear.NewSeq(l.ID, alphabet.BytesToLetters([]byte(l.Letters)), alpha)
+	ls.Annotation = a
+	return ls, nil
+}
@@ -0,0 +1,158 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/store/step"
+)
+
+// A Segment is a mapping between an interval of a Contig and an interval of one of
+// its members. Member coordinates are zero-based positions in the member as it was
+// when inserted into the Contig, so they are unaffected by subsequent calls to the
+// Contig's RevComp and Reverse methods. Strand is seq.Minus if the member letters
+// are complemented relative to the Contig and seq.Plus otherwise.
+type Segment struct {
+	Member                 seq.Sequence
+	Start, End             int
+	MemberStart, MemberEnd int
+	Strand                 seq.Strand
+}
+
+// ToMember returns the member providing the letter at position i of the Contig
+// under the Contig's Policy, the corresponding position in member coordinates and
+// the relative strand of the member.
+func (c *Contig) ToMember(i int) (m seq.Sequence, pos int, strand seq.Strand, err error) {
+	if c.vector == nil {
+		return nil, 0, seq.None, errEmpty
+	}
+	j := c.base(c.normalize(i))
+	e, err := c.vector.At(j)
+	if err != nil {
+		return nil, 0, seq.None, err
+	}
+	ss, ok := e.(seqStep)
+	if !ok {
+		return nil, 0, seq.None, errors.New("contig: no sequence at specified position")
+	}
+	p := c.policy.choose(j, ss)
+	return p.s, p.offset(j), c.frameOf(p).strand(), nil
+}
+
+// FromMember returns the position in the Contig corresponding to position pos in
+// member coordinates of the member with the given ID, and the relative strand of
+// the member.
+func (c *Contig) FromMember(id string, pos int) (i int, strand seq.Strand, err error) {
+	p, ok := c.index[id]
+	if !ok {
+		return 0, seq.None, errors.New("contig: no member with ID")
+	}
+	if pos < 0 || pos >= p.s.Len() {
+		return 0, seq.None, errors.New("contig: position out of range")
+	}
+	if p.reversed {
+		pos = p.s.Len() - 1 - pos
+	}
+	j := p.start + pos
+	if p.wrap != 0 && j >= c.vector.End() {
+		j -= p.wrap
+	}
+	return c.pos(j), c.frameOf(p).strand(), nil
+}
+
+// ToMembers returns the segments mapping the interval [start, end) of the Contig
+// onto its members. Where members overlap, each position is mapped to the member
+// providing its letter under the Contig's Policy, as for ToMember. Gaps and ground
+// state positions are not mapped.
+func (c *Contig) ToMembers(start, end int) ([]Segment, error) {
+	var (
+		segs []Segment
+		last *placement
+	)
+	err := c.do(start, end, func(start, end int, e step.Equaler) {
+		ss, ok := e.(seqStep)
+		if !ok {
+			last = nil
+			return
+		}
+		c.choose(start, end, ss, func(start, end int, p *placement) {
+			if n := len(segs); n != 0 && last == p && segs[n-1].End == start {
+				segs[n-1] = c.segment(p, segs[n-1].Start, end)
+				return
+			}
+			segs = append(segs, c.segment(p, start, end))
+			last = p
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+	return segs, nil
+}
+
+// FromMemberInterval returns the segment mapping the interval [start, end) in member
+// coordinates of the member with the given ID onto the Contig.
+func (c *Contig) FromMemberInterval(id string, start, end int) (Segment, error) {
+	p, ok := c.index[id]
+	if !ok {
+		return Segment{}, errors.New("contig: no member with ID")
+	}
+	if start < 0 || end > p.s.Len() || start > end {
+		return Segment{}, errors.New("contig: interval out of range")
+	}
+	if p.reversed {
+		start, end = p.s.Len()-end, p.s.Len()-start
+	}
+	start, end = c.interval(p.start+start, p.start+end)
+	return c.segment(p, start, end), nil
+}
+
+// segment returns the Segment for the Contig interval [start, end) of p.
+func (c *Contig) segment(p *placement, start, end int) Segment {
+	bs, be := c.toBase(start, end)
+	if bs < p.start {
+		bs, be = bs+p.wrap, be+p.wrap
+	}
+	ms, me := bs-p.start, be-p.start
+	if p.reversed {
+		ms, me = p.s.Len()-me, p.s.Len()-ms
+	}
+	return Segment{
+		Member:      p.s,
+		Start:       start,
+		End:         end,
+		MemberStart: ms,
+		MemberEnd:   me,
+		Strand:      c.frameOf(p).strand(),
+	}
+}
+
+// choose calls fn for each run of positions of the interval [start, end) of the
+// Contig, covered by the members in s, that takes its letters from the same member
+// under the Contig's Policy.
+func (c *Contig) choose(start, end int, s seqStep, fn func(start, end int, p *placement)) {
+	if len(s) == 1 || c.policy == FirstWins || c.policy == LastWins {
+		fn(start, end, c.policy.choose(c.base(start), s))
+		return
+	}
+	from, p := start, c.policy.choose(c.base(start), s)
+	for i := start + 1; i < end; i++ {
+		if q := c.policy.choose(c.base(i), s); q != p {
+			fn(from, i, p)
+			from, p = i, q
+		}
+	}
+	fn(from, end, p)
+}
+
+// strand returns the strand of a member relative to the Contig.
+func (f frame) strand() seq.Strand {
+	if f.complemented {
+		return seq.Minus
+	}
+	return seq.Plus
+}
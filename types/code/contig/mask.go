@@ -0,0 +1,249 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/biogo/biogo/alphabet"
+)
+
+// A Masking specifies how masked positions of a Contig are rendered.
+type Masking int
+
+const (
+	NoMasking   Masking = iota // Masked positions are rendered unaltered.
+	SoftMasking                // Masked letters are rendered in lower case.
+	HardMasking                // Masked letters are rendered as the upper case ambiguous letter.
+)
+
+func (m Masking) String() string {
+	switch m {
+	case NoMasking:
+		return "NoMasking"
+	case SoftMasking:
+		return "SoftMasking"
+	case HardMasking:
+		return "HardMasking"
+	}
+	return fmt.Sprintf("Masking(%d)", int(m))
+}
+
+func parseMasking(s string) (Masking, error) {
+	for _, m := range []Masking{NoMasking, SoftMasking, HardMasking} {
+		if m.String() == s {
+			return m, nil
+		}
+	}
+	return 0, fmt.Errorf("contig: unknown masking %q", s)
+}
+
+// SetMasking sets how masked positions of the Contig are rendered by At, Slice and
+// Format. Changing the masking does not alter the masked intervals.
+func (c *Contig) SetMasking(m Masking) {
+	c.checkWritable()
+	c.masking = m
+}
+
+// Masking returns how masked positions of the Contig are rendered.
+func (c *Contig) Masking() Masking { return c.masking }
+
+// Mask marks the interval [start, end) of the Contig as masked. Masks overlay
+// the members of the Contig without altering them and only affect positions
+// covered by a member. Masks are moved with their letters by RevComp and
+// Reverse. The interval of a circular Contig may extend past the Contig's end
+// to wrap its origin.
+func (c *Contig) Mask(start, end int) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	err := c.checkMask(start, end)
+	if err != nil {
+		return err
+	}
+	c.mask(start, end)
+	return nil
+}
+
+// MaskIntervals marks each of the intervals in iv as masked as described for Mask.
+// If any interval is invalid, no interval is masked.
+func (c *Contig) MaskIntervals(iv []Interval) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	for _, r := range iv {
+		err := c.checkMask(r.Start, r.End)
+		if err != nil {
+			return err
+		}
+	}
+	for _, r := range iv {
+		c.mask(r.Start, r.End)
+	}
+	return nil
+}
+
+// ClearMasks removes all masked intervals from the Contig.
+func (c *Contig) ClearMasks() {
+	c.checkWritable()
+	c.masks = nil
+}
+
+// Masked returns the maximal masked intervals of the Contig ordered by position.
+func (c *Contig) Masked() []Interval {
+	if len(c.masks) == 0 {
+		return nil
+	}
+	iv := make([]Interval, 0, len(c.masks))
+	for _, r := range c.masks {
+		start, end := c.interval(r.Start, r.End)
+		iv = append(iv, Interval{Start: start, End: end})
+	}
+	sort.Sort(byInterval(iv))
+	merged := iv[:1]
+	for _, r := range iv[1:] {
+		if last := &merged[len(merged)-1]; r.Start <= last.End {
+			last.End = max(last.End, r.End)
+			continue
+		}
+		merged = append(merged, r)
+	}
+	return merged
+}
+
+type byInterval []Interval
+
+func (iv byInterval) Len() int           { return len(iv) }
+func (iv byInterval) Less(i, j int) bool { return iv[i].Start < iv[j].Start }
+func (iv byInterval) Swap(i, j int)      { iv[i], iv[j] = iv[j], iv[i] }
+
+func (c *Contig) checkMask(start, end int) error {
+	if c.vector == nil || start > end {
+		return errors.New("contig: invalid mask interval")
+	}
+	if c.IsCircular() {
+		if start < c.Start() || start >= c.End() || end-start > c.Len() {
+			return errors.New("contig: mask out of range")
+		}
+	} else if start < c.Start() || end > c.End() {
+		return errors.New("contig: mask out of range")
+	}
+	return nil
+}
+
+// mask marks the interval [start, end) in the Contig's current coordinates
+// as masked.
+func (c *Contig) mask(start, end int) {
+	n := end - start
+	start, end = c.toBase(start, end)
+	wrap := 0
+	if c.IsCircular() {
+		wrap = c.Len()
+		start = c.vector.Start() + mod(start-c.vector.Start(), wrap)
+		end = start + n
+	}
+	for _, r := range c.ranges(start, end, wrap) {
+		c.addMask(r[0], r[1])
+	}
+}
+
+// addMask adds the base interval [start, end) to the Contig's masks.
+func (c *Contig) addMask(start, end int) { c.masks = addInterval(c.masks, start, end) }
+
+// isMasked returns whether base position i is masked.
+func (c *Contig) isMasked(i int) bool { return inIntervals(c.masks, i) }
+
+// maskLetter returns l rendered according to the Contig's Masking.
+func (c *Contig) maskLetter(l alphabet.Letter) alphabet.Letter {
+	switch c.masking {
+	case SoftMasking:
+		if 'A' <= l && l <= 'Z' {
+			l += 'a' - 'A'
+		}
+	case HardMasking:
+		l = c.Alpha.Ambiguous()
+		if 'a' <= l && l <= 'z' {
+			l -= 'a' - 'A'
+		}
+	}
+	return l
+}
+
+// ReadBEDIntervals reads BED formatted intervals from r and returns them keyed
+// by chromosome name. Only the first three fields of each line are used; track,
+// browser and comment lines are ignored.
+func ReadBEDIntervals(r io.Reader) (map[string][]Interval, error) {
+	iv := make(map[string][]Interval)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" || line[0] == '#' || strings.HasPrefix(line, "track") || strings.HasPrefix(line, "browser") {
+			continue
+		}
+		f := strings.Fields(line)
+		if len(f) < 3 {
+			return nil, fmt.Errorf("contig: bed line %d: too few fields", n)
+		}
+		start, err := strconv.Atoi(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("contig: bed line %d: %v", n, err)
+		}
+		end, err := strconv.Atoi(f[2])
+		if err != nil {
+			return nil, fmt.Errorf("contig: bed line %d: %v", n, err)
+		}
+		if start < 0 || end < start {
+			return nil, fmt.Errorf("contig: bed line %d: invalid range", n)
+		}
+		iv[f[0]] = append(iv[f[0]], Interval{Start: start, End: end})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
+
+// ReadRepeatMasker reads the repeat intervals of a RepeatMasker .out file from r
+// and returns them keyed by query sequence name. The one-based closed intervals of
+// the file are returned as zero-based half-open intervals. Header lines are ignored.
+func ReadRepeatMasker(r io.Reader) (map[string][]Interval, error) {
+	iv := make(map[string][]Interval)
+	sc := bufio.NewScanner(r)
+	for n := 1; sc.Scan(); n++ {
+		f := strings.Fields(sc.Text())
+		if len(f) == 0 {
+			continue
+		}
+		if _, err := strconv.Atoi(f[0]); err != nil {
+			// Header lines do not begin with a score.
+			continue
+		}
+		if len(f) < 7 {
+			return nil, fmt.Errorf("contig: repeatmasker line %d: too few fields", n)
+		}
+		start, err := strconv.Atoi(f[5])
+		if err != nil {
+			return nil, fmt.Errorf("contig: repeatmasker line %d: %v", n, err)
+		}
+		end, err := strconv.Atoi(f[6])
+		if err != nil {
+			return nil, fmt.Errorf("contig: repeatmasker line %d: %v", n, err)
+		}
+		if start < 1 || end < start {
+			return nil, fmt.Errorf("contig: repeatmasker line %d: invalid range", n)
+		}
+		iv[f[4]] = append(iv[f[4]], Interval{Start: start - 1, End: end})
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return iv, nil
+}
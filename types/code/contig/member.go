@@ -0,0 +1,161 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/store/step"
+)
+
+// A Member describes a sequence held by a Contig and its placement in the Contig.
+// Start and End are given in the Contig's current coordinates and Strand is the
+// strand of the member sequence relative to the Contig, as described for Segment.
+type Member struct {
+	Seq        seq.Sequence
+	Start, End int
+	Strand     seq.Strand
+}
+
+func (c *Contig) member(p *placement) Member {
+	start, end := c.span(p)
+	return Member{Seq: p.s, Start: start, End: end, Strand: c.frameOf(p).strand()}
+}
+
+// Members returns the member sequences of the Contig in insertion order.
+func (c *Contig) Members() []Member {
+	m := make([]Member, len(c.members))
+	for i, p := range c.members {
+		m[i] = c.member(p)
+	}
+	return m
+}
+
+// Lookup returns the member of the Contig with the given ID. If no member
+// has the ID, ok is returned false.
+func (c *Contig) Lookup(id string) (m Member, ok bool) {
+	p, ok := c.index[id]
+	if !ok {
+		return Member{}, false
+	}
+	return c.member(p), true
+}
+
+// Remove removes the member with the given ID from the Contig and returns it.
+// Positions covered only by the removed member are returned to the Contig's
+// ground state and features anchored to the member are removed.
+func (c *Contig) Remove(id string) (seq.Sequence, error) {
+	if c.frozen {
+		return nil, errSnapshot
+	}
+	p, ok := c.index[id]
+	if !ok {
+		return nil, errors.New("contig: no member with ID")
+	}
+	return p.s, c.remove(p)
+}
+
+// RemoveAt removes the most recently inserted member covering position i of
+// the Contig and returns it. Positions outside a circular Contig are wrapped
+// onto it.
+func (c *Contig) RemoveAt(i int) (seq.Sequence, error) {
+	if c.frozen {
+		return nil, errSnapshot
+	}
+	if c.vector == nil {
+		return nil, errEmpty
+	}
+	e, err := c.vector.At(c.base(c.normalize(i)))
+	if err != nil {
+		return nil, err
+	}
+	ss, ok := e.(seqStep)
+	if !ok {
+		return nil, errors.New("contig: no sequence at specified position")
+	}
+	p := ss[len(ss)-1]
+	return p.s, c.remove(p)
+}
+
+func (c *Contig) remove(p *placement) error {
+	for _, r := range c.extents(p) {
+		err := c.vector.ApplyRange(r[0], r[1], func(e step.Equaler) step.Equaler {
+			ss, ok := e.(seqStep)
+			if !ok {
+				return e
+			}
+			var rest seqStep
+			for _, m := range ss {
+				if m != p {
+					rest = append(rest, m)
+				}
+			}
+			if len(rest) == 0 {
+				return c.vector.Zero
+			}
+			return rest
+		})
+		if err != nil {
+			return err
+		}
+	}
+	for i, m := range c.members {
+		if m == p {
+			c.members = append(c.members[:i], c.members[i+1:]...)
+			break
+		}
+	}
+	delete(c.index, p.s.Name())
+	c.dropFeatures(p)
+	return nil
+}
+
+// Replace replaces the member with the given ID with s. The replacement is placed
+// at the start position and in the orientation of the member it replaces and
+// takes its place in the insertion order of the Contig, so overlaps are resolved
+// as they were for the replaced member. Features anchored to the replaced member
+// are anchored to s unless they lie beyond the end of s, in which case they are
+// removed. The alphabet of s must match the Contig's alphabet and the name of s
+// must not match that of another member.
+func (c *Contig) Replace(id string, s seq.Sequence) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	old, ok := c.index[id]
+	if !ok {
+		return errors.New("contig: no member with ID")
+	}
+	if s.Alphabet() != c.Alphabet() {
+		return errors.New("contig: alphabet mismatch")
+	}
+	if o, ok := c.index[s.Name()]; ok && o != old {
+		return errors.New("contig: duplicate member ID")
+	}
+	start, _ := c.span(old)
+	switch {
+	case c.IsCircular():
+		if s.Len() > c.Len() {
+			return errors.New("contig: sequence longer than circular contig")
+		}
+	case !c.vector.Relaxed && start+s.Len() > c.End():
+		return errors.New("contig: sequence out of range")
+	}
+	p := c.newPlacement(s, start, c.frameOf(old))
+	for i, m := range c.members {
+		if m == old {
+			c.members[i] = p
+			break
+		}
+	}
+	delete(c.index, id)
+	for _, a := range c.features {
+		if a.anchor == old && a.end <= s.Len() {
+			a.anchor = p
+		}
+	}
+	c.dropFeatures(old)
+	return c.rebuild()
+}
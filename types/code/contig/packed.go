@@ -0,0 +1,362 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/store/step"
+)
+
+// twoBit is a nucleic acid sequence packed as two bits per base in the UCSC
+// .2bit layout. Bases are coded T=0, C=1, A=2 and G=3 with the first base of
+// each byte in its most significant bits. Letters other than A, C, G and T
+// are held as runs in nBlocks and lower case letters as runs in maskBlocks.
+type twoBit struct {
+	n          int
+	bits       []byte
+	nBlocks    []Interval
+	maskBlocks []Interval
+
+	// count specifies that only the length
+	// and blocks are recorded.
+	count bool
+}
+
+const twoBitLetters = "TCAG"
+
+func twoBitCode(l alphabet.Letter) (byte, bool) {
+	switch l {
+	case 'T', 't':
+		return 0, true
+	case 'C', 'c':
+		return 1, true
+	case 'A', 'a':
+		return 2, true
+	case 'G', 'g':
+		return 3, true
+	}
+	return 0, false
+}
+
+func isLower(l alphabet.Letter) bool { return 'a' <= l && l <= 'z' }
+
+// append appends l to the packed sequence.
+func (t *twoBit) append(l alphabet.Letter) {
+	i := t.n
+	t.n++
+	code, ok := twoBitCode(l)
+	if !ok {
+		t.nBlocks = extendRun(t.nBlocks, i)
+	}
+	if isLower(l) {
+		t.maskBlocks = extendRun(t.maskBlocks, i)
+	}
+	if t.count {
+		return
+	}
+	if i%4 == 0 {
+		t.bits = append(t.bits, 0)
+	}
+	t.bits[i/4] |= code << (6 - 2*uint(i%4))
+}
+
+// code returns the two bit code at position i.
+func (t *twoBit) code(i int) byte {
+	return t.bits[i/4] >> (6 - 2*uint(i%4)) & 0x3
+}
+
+// at returns the letter at position i.
+func (t *twoBit) at(i int) alphabet.Letter {
+	var l alphabet.Letter
+	if inIntervals(t.nBlocks, i) {
+		l = 'N'
+	} else {
+		l = alphabet.Letter(twoBitLetters[t.code(i)])
+	}
+	if inIntervals(t.maskBlocks, i) {
+		l += 'a' - 'A'
+	}
+	return l
+}
+
+// set sets the letter at position i to l.
+func (t *twoBit) set(i int, l alphabet.Letter) error {
+	if i < 0 || i >= t.n {
+		return errors.New("contig: position out of range")
+	}
+	code, ok := twoBitCode(l)
+	if ok {
+		t.nBlocks = removeInterval(t.nBlocks, i, i+1)
+	} else {
+		t.nBlocks = addInterval(t.nBlocks, i, i+1)
+	}
+	if isLower(l) {
+		t.maskBlocks = addInterval(t.maskBlocks, i, i+1)
+	} else {
+		t.maskBlocks = removeInterval(t.maskBlocks, i, i+1)
+	}
+	shift := 6 - 2*uint(i%4)
+	t.bits[i/4] = t.bits[i/4]&^(0x3<<shift) | code<<shift
+	return nil
+}
+
+// letters returns the unpacked letters of the sequence.
+func (t *twoBit) letters() alphabet.Letters {
+	l := make(alphabet.Letters, t.n)
+	for i := range l {
+		l[i] = t.at(i)
+	}
+	return l
+}
+
+// reverse reverses the packed sequence, complementing the bases if comp is true.
+func (t *twoBit) reverse(comp bool) {
+	bits := make([]byte, len(t.bits))
+	for i := 0; i < t.n; i++ {
+		code := t.code(t.n - 1 - i)
+		if comp {
+			// T and A, and C and G differ in their high bit.
+			code ^= 0x2
+		}
+		bits[i/4] |= code << (6 - 2*uint(i%4))
+	}
+	t.bits = bits
+	t.nBlocks = mirror(t.nBlocks, t.n)
+	t.maskBlocks = mirror(t.maskBlocks, t.n)
+}
+
+func (t *twoBit) clone() twoBit {
+	return twoBit{
+		n:          t.n,
+		bits:       append([]byte(nil), t.bits...),
+		nBlocks:    append([]Interval(nil), t.nBlocks...),
+		maskBlocks: append([]Interval(nil), t.maskBlocks...),
+	}
+}
+
+// extendRun adds position i to the last run in iv if it abuts i and as a new
+// run otherwise.
+func extendRun(iv []Interval, i int) []Interval {
+	if n := len(iv); n != 0 && iv[n-1].End == i {
+		iv[n-1].End++
+		return iv
+	}
+	return append(iv, Interval{Start: i, End: i + 1})
+}
+
+// addInterval adds [start, end) to the sorted and merged intervals in iv,
+// retaining the intervals sorted and merged.
+func addInterval(iv []Interval, start, end int) []Interval {
+	if start >= end {
+		return iv
+	}
+	i := sort.Search(len(iv), func(i int) bool { return iv[i].End >= start })
+	j := i
+	for ; j < len(iv) && iv[j].Start <= end; j++ {
+		start, end = min(start, iv[j].Start), max(end, iv[j].End)
+	}
+	return append(iv[:i], append([]Interval{{Start: start, End: end}}, iv[j:]...)...)
+}
+
+// removeInterval removes [start, end) from the sorted and merged intervals in iv.
+func removeInterval(iv []Interval, start, end int) []Interval {
+	var out []Interval
+	for _, r := range iv {
+		if r.End <= start || r.Start >= end {
+			out = append(out, r)
+			continue
+		}
+		if r.Start < start {
+			out = append(out, Interval{Start: r.Start, End: start})
+		}
+		if r.End > end {
+			out = append(out, Interval{Start: end, End: r.End})
+		}
+	}
+	return out
+}
+
+// inIntervals returns whether i is within the sorted and merged intervals in iv.
+func inIntervals(iv []Interval, i int) bool {
+	j := sort.Search(len(iv), func(j int) bool { return iv[j].End > i })
+	return j < len(iv) && iv[j].Start <= i
+}
+
+// mirror returns the intervals of iv reflected within [0, n).
+func mirror(iv []Interval, n int) []Interval {
+	m := make([]Interval, len(iv))
+	for i, r := range iv {
+		m[len(iv)-1-i] = Interval{Start: n - r.End, End: n - r.Start}
+	}
+	return m
+}
+
+// Packed is a nucleic acid sequence held packed as two bits per base with
+// runs of N and of lower case letters held as blocks, as in the UCSC .2bit
+// format. A Packed sequence uses roughly a quarter of the memory of the
+// equivalent linear.Seq. Letters other than A, C, G and T in either case are
+// held as N, retaining their case.
+type Packed struct {
+	seq.Annotation
+	twoBit
+}
+
+// NewPacked returns a new Packed sequence holding the letters in b.
+func NewPacked(id string, b []alphabet.Letter, alpha alphabet.Alphabet) *Packed {
+	p := &Packed{Annotation: seq.Annotation{ID: id, Alpha: alpha, Strand: seq.Plus}}
+	for _, l := range b {
+		p.append(l)
+	}
+	return p
+}
+
+// Packable returns whether the letters of s can be held by a Packed sequence
+// without loss. Sequences carrying quality scores are not packable.
+func Packable(s seq.Sequence) bool {
+	if _, ok := s.(encoder); ok {
+		return false
+	}
+	for i := s.Start(); i < s.End(); i++ {
+		switch s.At(i).L {
+		case 'A', 'C', 'G', 'T', 'N', 'a', 'c', 'g', 't', 'n':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Len returns the length of the sequence.
+func (p *Packed) Len() int { return p.n }
+
+// Start returns the start position of the sequence in global coordinates.
+func (p *Packed) Start() int { return p.Offset }
+
+// End returns the end position of the sequence in global coordinates.
+func (p *Packed) End() int { return p.Offset + p.n }
+
+// At returns the letter at position i with the default quality.
+func (p *Packed) At(i int) alphabet.QLetter {
+	return alphabet.QLetter{L: p.at(i - p.Offset), Q: seq.DefaultQphred}
+}
+
+// Set sets the letter at position i to l. The quality of l is ignored.
+func (p *Packed) Set(i int, l alphabet.QLetter) error {
+	return p.set(i-p.Offset, l.L)
+}
+
+// New returns an empty Packed sequence with the same alphabet.
+func (p *Packed) New() seq.Sequence {
+	return &Packed{Annotation: seq.Annotation{Alpha: p.Alpha, Strand: seq.Plus}}
+}
+
+// Clone returns a copy of the sequence.
+func (p *Packed) Clone() seq.Sequence {
+	return &Packed{Annotation: *p.CloneAnnotation(), twoBit: p.clone()}
+}
+
+// Slice returns the unpacked letters of the sequence as an alphabet.Letters.
+// Changes to the returned slice are not reflected in the sequence.
+func (p *Packed) Slice() alphabet.Slice { return p.letters() }
+
+// SetSlice packs the letters of sl into the sequence. SetSlice will panic if sl
+// is not an alphabet.Letters or an alphabet.QLetters; qualities are discarded.
+func (p *Packed) SetSlice(sl alphabet.Slice) {
+	p.twoBit = twoBit{}
+	switch sl := sl.(type) {
+	case alphabet.Letters:
+		for _, l := range sl {
+			p.append(l)
+		}
+	case alphabet.QLetters:
+		for _, l := range sl {
+			p.append(l.L)
+		}
+	default:
+		panic("contig: unsupported slice type")
+	}
+}
+
+// RevComp reverse complements the sequence.
+func (p *Packed) RevComp() {
+	p.reverse(true)
+	p.Strand = -p.Strand
+}
+
+// Reverse reverses the sequence.
+func (p *Packed) Reverse() {
+	p.reverse(false)
+	p.Strand = seq.None
+}
+
+// Format is a fmt.Formatter helper. It provides support for the %v, %s and %a
+// (FASTA output) verbs.
+func (p *Packed) Format(fs fmt.State, c rune) {
+	if p == nil {
+		fmt.Fprint(fs, "<nil>")
+		return
+	}
+	switch c {
+	case 'v', 's':
+		if !fs.Flag('-') {
+			fmt.Fprintf(fs, "%q ", p.ID)
+		}
+	case 'a':
+		fmt.Fprintf(fs, ">%s", p.ID)
+		if p.Desc != "" {
+			fmt.Fprintf(fs, " %s", p.Desc)
+		}
+		fmt.Fprintln(fs)
+	default:
+		fmt.Fprintf(fs, "%%!%c(*contig.Packed=%.10s)", c, p)
+		return
+	}
+	var buf [4096]byte
+	for i := 0; i < p.n; {
+		n := min(len(buf), p.n-i)
+		for j := range buf[:n] {
+			buf[j] = byte(p.at(i + j))
+		}
+		fs.Write(buf[:n])
+		i += n
+	}
+}
+
+// Pack replaces the members of the Contig that are Packable with Packed
+// sequences holding the same letters and annotation.
+// Positions, orientation and features of the replaced members are retained.
+func (c *Contig) Pack() {
+	c.checkWritable()
+	for _, m := range c.members {
+		if _, ok := m.s.(*Packed); ok || !Packable(m.s) {
+			continue
+		}
+		p := &Packed{Annotation: *m.s.CloneAnnotation()}
+		for i := m.s.Start(); i < m.s.End(); i++ {
+			p.append(m.s.At(i).L)
+		}
+		m.s = p
+	}
+}
+
+// pack returns the letters of the Contig in its current orientation packed as
+// a twoBit. If count is true, only the length and blocks are recorded.
+func (c *Contig) pack(count bool) *twoBit {
+	t := &twoBit{count: count}
+	if c.vector == nil {
+		return t
+	}
+	c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+		for i := start; i < end; i++ {
+			t.append(c.letter(i, e).L)
+		}
+	})
+	return t
+}
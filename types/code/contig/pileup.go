@@ -0,0 +1,158 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/biogo/biogo/seq"
+	"github.com/biogo/store/step"
+)
+
+// A Coverage is a maximal interval of a Contig covered by the same set of members.
+// Members are given in insertion order and are nil for gaps and ground state runs.
+type Coverage struct {
+	Start, End int
+	Members    []seq.Sequence
+}
+
+// Depth returns the number of members covering the interval.
+func (cv Coverage) Depth() int { return len(cv.Members) }
+
+// An Interval is a half-open interval of a Contig.
+type Interval struct {
+	Start, End int
+}
+
+// Pileup returns the coverage of the interval [start, end) of the Contig by its
+// members, ordered by position.
+func (c *Contig) Pileup(start, end int) ([]Coverage, error) {
+	var cov []Coverage
+	err := c.do(start, end, func(start, end int, e step.Equaler) {
+		var ss seqStep
+		if s, ok := e.(seqStep); ok {
+			ss = s
+		}
+		if n := len(cov); n != 0 && cov[n-1].End == start && sameMembers(cov[n-1].Members, ss) {
+			cov[n-1].End = end
+			return
+		}
+		cv := Coverage{Start: start, End: end}
+		for _, p := range ss {
+			cv.Members = append(cv.Members, p.s)
+		}
+		cov = append(cov, cv)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return cov, nil
+}
+
+func sameMembers(m []seq.Sequence, ss seqStep) bool {
+	if len(m) != len(ss) {
+		return false
+	}
+	for i, p := range ss {
+		if m[i] != p.s {
+			return false
+		}
+	}
+	return true
+}
+
+// DepthHistogram returns the number of positions of the Contig covered by each
+// number of members, indexed by depth.
+func (c *Contig) DepthHistogram() []int {
+	var h []int
+	if c.vector == nil {
+		return h
+	}
+	c.vector.Do(func(start, end int, e step.Equaler) {
+		var d int
+		if ss, ok := e.(seqStep); ok {
+			d = len(ss)
+		}
+		for len(h) <= d {
+			h = append(h, 0)
+		}
+		h[d] += end - start
+	})
+	return h
+}
+
+// ZeroCoverage returns the maximal intervals of the Contig not covered by any
+// member, ordered by position.
+func (c *Contig) ZeroCoverage() []Interval {
+	var runs []Interval
+	if c.vector == nil {
+		return runs
+	}
+	c.do(c.Start(), c.End(), func(start, end int, e step.Equaler) {
+		if _, ok := e.(seqStep); ok {
+			return
+		}
+		if n := len(runs); n != 0 && runs[n-1].End == start {
+			runs[n-1].End = end
+			return
+		}
+		runs = append(runs, Interval{Start: start, End: end})
+	})
+	return runs
+}
+
+// BedGraphWriter writes the member coverage depth of Contigs in bedGraph format.
+type BedGraphWriter struct {
+	w      io.Writer
+	header bool
+
+	// Name is written as the name of the bedGraph
+	// track if not empty.
+	Name string
+}
+
+// NewBedGraphWriter returns a new BedGraphWriter that writes to w.
+func NewBedGraphWriter(w io.Writer) *BedGraphWriter {
+	return &BedGraphWriter{w: w}
+}
+
+// Write writes the coverage depth of c as bedGraph lines with the ID of c as the
+// chromosome name. Adjacent intervals with equal depth are written as a single line.
+// Coordinates are relative to the start of c, so the first line always begins at
+// position 0.
+func (w *BedGraphWriter) Write(c *Contig) error {
+	if !w.header {
+		var err error
+		if w.Name != "" {
+			_, err = fmt.Fprintf(w.w, "track type=bedGraph name=%q\n", w.Name)
+		} else {
+			_, err = fmt.Fprintln(w.w, "track type=bedGraph")
+		}
+		if err != nil {
+			return err
+		}
+		w.header = true
+	}
+	if c.vector == nil {
+		return nil
+	}
+	cov, err := c.Pileup(c.Start(), c.End())
+	if err != nil {
+		return err
+	}
+	for i := 0; i < len(cov); {
+		j, d := i+1, cov[i].Depth()
+		for j < len(cov) && cov[j].Depth() == d {
+			j++
+		}
+		_, err = fmt.Fprintf(w.w, "%s\t%d\t%d\t%d\n", c.ID, cov[i].Start-c.Start(), cov[j-1].End-c.Start(), d)
+		if err != nil {
+			return err
+		}
+		i = j
+	}
+	return nil
+}
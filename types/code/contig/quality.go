@@ -0,0 +1,59 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"io"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/store/step"
+)
+
+// SetGroundQuality sets the quality reported for gap and ground state positions
+// of the Contig to q. The default ground quality is seq.DefaultQphred.
+func (c *Contig) SetGroundQuality(q alphabet.Qphred) {
+	c.checkWritable()
+	c.groundQ = q
+}
+
+// GroundQuality returns the quality reported for gap and ground state positions.
+func (c *Contig) GroundQuality() alphabet.Qphred { return c.groundQ }
+
+// Encoding returns the quality encoding used for FASTQ output of the Contig.
+func (c *Contig) Encoding() alphabet.Encoding { return c.encode }
+
+// SetEncoding sets the quality encoding used for FASTQ output of the Contig to e.
+func (c *Contig) SetEncoding(e alphabet.Encoding) error {
+	if c.frozen {
+		return errSnapshot
+	}
+	c.encode = e
+	return nil
+}
+
+// encoder is a sequence carrying encoded quality scores.
+type encoder interface {
+	Encoding() alphabet.Encoding
+}
+
+// hasQuality returns whether any member of the Contig carries quality scores.
+func (c *Contig) hasQuality() bool {
+	for _, p := range c.members {
+		if _, ok := p.s.(encoder); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// formatQuality writes the encoded qualities of the first n positions of the
+// Contig to w.
+func (c *Contig) formatQuality(w io.Writer, n int) {
+	c.do(c.Start(), c.Start()+n, func(start, end int, e step.Equaler) {
+		for i := start; i < end; i++ {
+			w.Write([]byte{c.letter(i, e).Q.Encode(c.encode)})
+		}
+	})
+}
@@ -0,0 +1,528 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/biogo/biogo/seq"
+)
+
+// maxAlignmentLine is the maximum length of a line of a PAF or SAM file. SAM
+// records of assembled contigs hold the complete contig sequence.
+const maxAlignmentLine = math.MaxInt32
+
+// An Alignment is an alignment of a query sequence to a target reference
+// sequence. Query coordinates are zero-based half-open positions on the forward
+// strand of the query and target coordinates are zero-based half-open positions
+// on the forward strand of the target. Strand is seq.Minus if the reverse
+// complement of the query aligns to the target.
+type Alignment struct {
+	Query                string
+	QueryLen             int
+	QueryStart, QueryEnd int
+
+	Strand seq.Strand
+
+	Target                 string
+	TargetLen              int
+	TargetStart, TargetEnd int
+
+	// Matches is the number of matching
+	// positions in the alignment.
+	Matches int
+
+	// MapQ is the mapping quality of the
+	// alignment, 255 if not available.
+	MapQ int
+}
+
+// ReadPAF reads the alignments of a PAF file from r. Optional tags are ignored.
+func ReadPAF(r io.Reader) ([]Alignment, error) {
+	var alns []Alignment
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxAlignmentLine)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" || line[0] == '#' {
+			continue
+		}
+		f := strings.Split(line, "\t")
+		if len(f) < 12 {
+			return nil, fmt.Errorf("contig: paf line %d: too few fields", n)
+		}
+		a := Alignment{Query: f[0], Target: f[5]}
+		for i, v := range []*int{
+			1: &a.QueryLen, 2: &a.QueryStart, 3: &a.QueryEnd,
+			6: &a.TargetLen, 7: &a.TargetStart, 8: &a.TargetEnd,
+			9: &a.Matches, 11: &a.MapQ,
+		} {
+			if v == nil {
+				continue
+			}
+			var err error
+			*v, err = strconv.Atoi(f[i])
+			if err != nil {
+				return nil, fmt.Errorf("contig: paf line %d: %v", n, err)
+			}
+		}
+		switch f[4] {
+		case "+":
+			a.Strand = seq.Plus
+		case "-":
+			a.Strand = seq.Minus
+		default:
+			return nil, fmt.Errorf("contig: paf line %d: invalid strand %q", n, f[4])
+		}
+		if a.QueryStart < 0 || a.QueryEnd < a.QueryStart || a.QueryEnd > a.QueryLen ||
+			a.TargetStart < 0 || a.TargetEnd < a.TargetStart || a.TargetEnd > a.TargetLen {
+			return nil, fmt.Errorf("contig: paf line %d: invalid range", n)
+		}
+		alns = append(alns, a)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return alns, nil
+}
+
+// SAM flag bits used by ReadSAM.
+const (
+	samReverse   = 0x10
+	samUnmapped  = 0x4
+	samSecondary = 0x100
+)
+
+// ReadSAM reads the alignments of a SAM file from r. Unmapped records and
+// secondary alignments are skipped. Target lengths are taken from @SQ header
+// lines and query lengths and positions from the CIGAR string, including clipped
+// regions. The number of matches is the number of '=' operations if the CIGAR
+// uses them, and otherwise the number of aligned positions less the substitutions
+// implied by the NM tag, if present.
+func ReadSAM(r io.Reader) ([]Alignment, error) {
+	var alns []Alignment
+	lens := make(map[string]int)
+	sc := bufio.NewScanner(r)
+	sc.Buffer(nil, maxAlignmentLine)
+	for n := 1; sc.Scan(); n++ {
+		line := sc.Text()
+		if line == "" {
+			continue
+		}
+		if line[0] == '@' {
+			if !strings.HasPrefix(line, "@SQ\t") {
+				continue
+			}
+			var (
+				name   string
+				length int
+			)
+			for _, t := range strings.Split(line, "\t")[1:] {
+				switch {
+				case strings.HasPrefix(t, "SN:"):
+					name = t[3:]
+				case strings.HasPrefix(t, "LN:"):
+					var err error
+					length, err = strconv.Atoi(t[3:])
+					if err != nil {
+						return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+					}
+				}
+			}
+			lens[name] = length
+			continue
+		}
+		f := strings.Split(line, "\t")
+		if len(f) < 11 {
+			return nil, fmt.Errorf("contig: sam line %d: too few fields", n)
+		}
+		flag, err := strconv.Atoi(f[1])
+		if err != nil {
+			return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+		}
+		if flag&(samUnmapped|samSecondary) != 0 || f[2] == "*" || f[5] == "*" {
+			continue
+		}
+		pos, err := strconv.Atoi(f[3])
+		if err != nil {
+			return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+		}
+		if pos < 1 {
+			return nil, fmt.Errorf("contig: sam line %d: invalid position", n)
+		}
+		mapq, err := strconv.Atoi(f[4])
+		if err != nil {
+			return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+		}
+		cig, err := parseCigar(f[5])
+		if err != nil {
+			return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+		}
+		if cig.ops == 0 {
+			continue
+		}
+
+		a := Alignment{
+			Query:       f[0],
+			QueryLen:    cig.queryLen,
+			QueryStart:  cig.clipStart,
+			QueryEnd:    cig.queryLen - cig.clipEnd,
+			Strand:      seq.Plus,
+			Target:      f[2],
+			TargetLen:   lens[f[2]],
+			TargetStart: pos - 1,
+			TargetEnd:   pos - 1 + cig.targetLen,
+			Matches:     cig.matches,
+			MapQ:        mapq,
+		}
+		if !cig.explicit {
+			for _, t := range f[11:] {
+				if !strings.HasPrefix(t, "NM:i:") {
+					continue
+				}
+				nm, err := strconv.Atoi(t[5:])
+				if err != nil {
+					return nil, fmt.Errorf("contig: sam line %d: %v", n, err)
+				}
+				a.Matches -= max(0, nm-cig.indels)
+				break
+			}
+		}
+		if flag&samReverse != 0 {
+			// SAM query positions refer to the reverse
+			// complement of a reverse strand query.
+			a.Strand = seq.Minus
+			a.QueryStart, a.QueryEnd = a.QueryLen-a.QueryEnd, a.QueryLen-a.QueryStart
+		}
+		alns = append(alns, a)
+	}
+	if err := sc.Err(); err != nil {
+		return nil, err
+	}
+	return alns, nil
+}
+
+// cigar holds the lengths described by a SAM CIGAR string.
+type cigar struct {
+	ops                int
+	queryLen           int
+	targetLen          int
+	clipStart, clipEnd int
+	matches            int
+	indels             int
+
+	// explicit specifies that matches
+	// are counted from '=' operations.
+	explicit bool
+}
+
+func parseCigar(s string) (cigar, error) {
+	var (
+		c       cigar
+		n       int
+		digits  bool
+		aligned bool
+	)
+	for i := 0; i < len(s); i++ {
+		b := s[i]
+		if '0' <= b && b <= '9' {
+			n = n*10 + int(b-'0')
+			digits = true
+			continue
+		}
+		if !digits {
+			return c, fmt.Errorf("invalid cigar %q", s)
+		}
+		switch b {
+		case 'M':
+			c.matches += n
+			c.queryLen += n
+			c.targetLen += n
+			aligned = true
+		case '=':
+			if !c.explicit {
+				c.explicit = true
+				c.matches = 0
+			}
+			c.matches += n
+			c.queryLen += n
+			c.targetLen += n
+			aligned = true
+		case 'X':
+			c.queryLen += n
+			c.targetLen += n
+			aligned = true
+		case 'I':
+			c.queryLen += n
+			c.indels += n
+		case 'D':
+			c.targetLen += n
+			c.indels += n
+		case 'N':
+			c.targetLen += n
+		case 'S', 'H':
+			c.queryLen += n
+			if aligned {
+				c.clipEnd += n
+			} else {
+				c.clipStart += n
+			}
+		case 'P':
+		default:
+			return c, fmt.Errorf("invalid cigar operation %q", b)
+		}
+		c.ops++
+		n, digits = 0, false
+	}
+	if digits {
+		return c, fmt.Errorf("invalid cigar %q", s)
+	}
+	if c.explicit {
+		c.indels = 0
+	}
+	return c, nil
+}
+
+// A Scaffolder builds scaffolds by placing query sequences on the positions
+// implied by their alignments to a set of reference sequences.
+type Scaffolder struct {
+	// MinMapQ is the minimum mapping
+	// quality of a used alignment.
+	MinMapQ int
+
+	// MinCoverage is the minimum fraction of a query
+	// that must be aligned for the query to be placed.
+	MinCoverage float64
+
+	// MinGap is the minimum length of a gap sized from
+	// the reference. Queries separated by less than
+	// MinGap, or overlapping on the reference, are
+	// separated by a gap of unknown size. Queries that
+	// abut on the reference are placed without a gap.
+	MinGap int
+
+	// UnknownGap is the length of gaps of unknown
+	// size. If zero, DefaultUnknownGap is used.
+	UnknownGap int
+}
+
+// scaffoldPlacement is the position of a query implied by its alignments to
+// a target.
+type scaffoldPlacement struct {
+	query  string
+	target string
+	strand seq.Strand
+
+	// start is the target position of the
+	// start of the oriented query.
+	start int
+
+	// aligned is the target interval
+	// covered by the alignments.
+	aligned Interval
+
+	matches  int
+	coverage int
+}
+
+// Scaffold returns a scaffold for each target sequence that has placed queries,
+// in order of the first alignment to each target, and the names of the sequences
+// in seqs that were not placed, in sorted order. Query sequences are obtained
+// from seqs, and must all share an alphabet.
+//
+// Each query is placed on the target and strand with the greatest number of
+// matches summed over its alignments, with the query's position taken from the
+// alignment on that target and strand with the most matches. Alignments with a
+// mapping quality below MinMapQ, and placements covering less than MinCoverage
+// of the query, are not used. A query is redundant and not placed when its
+// aligned region of the target is contained in that of a placed query with more
+// matches.
+//
+// Placed queries are ordered by position on the target, starting at position
+// zero of the scaffold, and are reverse complemented where they align to the
+// minus strand. The distance between adjacent queries on the target is marked as
+// a scaffold gap with alignment linkage evidence, or as a gap of unknown size if
+// the distance is less than MinGap. Each scaffold has the ID of its target.
+func (s *Scaffolder) Scaffold(alns []Alignment, seqs map[string]seq.Sequence) (scaffolds []*Contig, unplaced []string, err error) {
+	type key struct {
+		query, target string
+		strand        seq.Strand
+	}
+	var (
+		groups  = make(map[key]*scaffoldPlacement)
+		best    = make(map[key]int)
+		targets []string
+		seen    = make(map[string]bool)
+	)
+	for _, a := range alns {
+		if a.MapQ < s.MinMapQ {
+			continue
+		}
+		q, ok := seqs[a.Query]
+		if !ok {
+			return nil, nil, fmt.Errorf("contig: no sequence for %q", a.Query)
+		}
+		if q.Len() != a.QueryLen {
+			return nil, nil, fmt.Errorf("contig: length mismatch for %q", a.Query)
+		}
+		if !seen[a.Target] {
+			seen[a.Target] = true
+			targets = append(targets, a.Target)
+		}
+		k := key{a.Query, a.Target, a.Strand}
+		g, ok := groups[k]
+		if !ok {
+			g = &scaffoldPlacement{query: a.Query, target: a.Target, strand: a.Strand, aligned: Interval{a.TargetStart, a.TargetEnd}}
+			groups[k] = g
+		}
+		g.aligned.Start = min(g.aligned.Start, a.TargetStart)
+		g.aligned.End = max(g.aligned.End, a.TargetEnd)
+		g.matches += a.Matches
+		g.coverage += a.QueryEnd - a.QueryStart
+		if !ok || a.Matches > best[k] {
+			best[k] = a.Matches
+			if a.Strand == seq.Minus {
+				g.start = a.TargetStart - (a.QueryLen - a.QueryEnd)
+			} else {
+				g.start = a.TargetStart - a.QueryStart
+			}
+		}
+	}
+
+	// Choose the placement of each query.
+	chosen := make(map[string]*scaffoldPlacement)
+	for _, g := range groups {
+		c, ok := chosen[g.query]
+		if !ok || g.matches > c.matches || (g.matches == c.matches && lessPlacement(g, c)) {
+			chosen[g.query] = g
+		}
+	}
+	byTarget := make(map[string][]*scaffoldPlacement)
+	for q, g := range chosen {
+		if float64(min(g.coverage, seqs[q].Len())) < s.MinCoverage*float64(seqs[q].Len()) {
+			continue
+		}
+		byTarget[g.target] = append(byTarget[g.target], g)
+	}
+
+	placed := make(map[string]bool)
+	for _, t := range targets {
+		cand := byTarget[t]
+		if len(cand) == 0 {
+			continue
+		}
+		sort.Slice(cand, func(i, j int) bool {
+			if cand[i].matches != cand[j].matches {
+				return cand[i].matches > cand[j].matches
+			}
+			return cand[i].query < cand[j].query
+		})
+		var tiling []*scaffoldPlacement
+	outer:
+		for _, g := range cand {
+			for _, k := range tiling {
+				if k.aligned.Start <= g.aligned.Start && g.aligned.End <= k.aligned.End {
+					continue outer
+				}
+			}
+			tiling = append(tiling, g)
+		}
+		sort.Slice(tiling, func(i, j int) bool { return lessPlacement(tiling[i], tiling[j]) })
+
+		c, err := s.build(t, tiling, seqs)
+		if err != nil {
+			return nil, nil, err
+		}
+		for _, g := range tiling {
+			placed[g.query] = true
+		}
+		scaffolds = append(scaffolds, c)
+	}
+
+	for q := range seqs {
+		if !placed[q] {
+			unplaced = append(unplaced, q)
+		}
+	}
+	sort.Strings(unplaced)
+	return scaffolds, unplaced, nil
+}
+
+// lessPlacement returns whether a is before b on the target. Placements that
+// are otherwise equal are ordered by query, target and strand, so the order is
+// total.
+func lessPlacement(a, b *scaffoldPlacement) bool {
+	if a.start != b.start {
+		return a.start < b.start
+	}
+	if a.aligned.Start != b.aligned.Start {
+		return a.aligned.Start < b.aligned.Start
+	}
+	if a.query != b.query {
+		return a.query < b.query
+	}
+	if a.target != b.target {
+		return a.target < b.target
+	}
+	return a.strand < b.strand
+}
+
+// build returns the scaffold with the given ID holding the ordered placements
+// in tiling.
+func (s *Scaffolder) build(id string, tiling []*scaffoldPlacement, seqs map[string]seq.Sequence) (*Contig, error) {
+	unknown := s.UnknownGap
+	if unknown == 0 {
+		unknown = DefaultUnknownGap
+	}
+	alpha := seqs[tiling[0].query].Alphabet()
+	gaps := make([]Gap, len(tiling))
+	var n int
+	for i, g := range tiling {
+		q := seqs[g.query]
+		if q.Alphabet() != alpha {
+			return nil, errors.New("contig: alphabet mismatch")
+		}
+		if i != 0 {
+			prev := tiling[i-1]
+			d := g.start - (prev.start + seqs[prev.query].Len())
+			switch {
+			case d < 0 || (d > 0 && d < s.MinGap):
+				gaps[i] = Gap{Type: ScaffoldGap, Unknown: true, Linkage: true, Evidence: []Evidence{AlignGenus}}
+				d = unknown
+			case d > 0:
+				gaps[i] = Gap{Type: ScaffoldGap, Linkage: true, Evidence: []Evidence{AlignGenus}}
+			}
+			gaps[i].Start, gaps[i].End = n, n+d
+			n += d
+		}
+		n += q.Len()
+	}
+
+	c, err := New(id, n, alpha)
+	if err != nil {
+		return nil, err
+	}
+	for i, g := range tiling {
+		if gaps[i].Len() != 0 {
+			c.insertGap(gaps[i])
+		}
+		var f frame
+		if g.strand == seq.Minus {
+			f = frame{reversed: true, complemented: true}
+		}
+		err = c.insert(c.newPlacement(seqs[g.query], gaps[i].End, f))
+		if err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
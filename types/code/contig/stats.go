@@ -0,0 +1,163 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/biogo/store/step"
+)
+
+// Stats holds summary statistics for a collection of Contigs.
+type Stats struct {
+	Contigs int `json:"contigs"`
+	Members int `json:"members"`
+	Length  int `json:"length"`
+	Min     int `json:"min"`
+	Max     int `json:"max"`
+
+	// N50 is the length of the shortest Contig in the smallest
+	// set of Contigs covering at least half of the total length
+	// and L50 is the number of Contigs in that set.
+	N50 int `json:"n50"`
+	L50 int `json:"l50"`
+
+	// GenomeSize is the expected genome size used to calculate
+	// NG50 and LG50, which are analogous to N50 and L50. NG50
+	// and LG50 are zero if GenomeSize is zero or the Contigs
+	// cover less than half of GenomeSize.
+	GenomeSize int `json:"genome_size,omitempty"`
+	NG50       int `json:"ng50,omitempty"`
+	LG50       int `json:"lg50,omitempty"`
+
+	// Gaps and GapLength count the Gaps of the Contigs and
+	// the runs of ground state positions between members,
+	// as written by AGPWriter.
+	Gaps      int `json:"gaps"`
+	GapLength int `json:"gap_length"`
+
+	// GC is the fraction of unambiguous bases that are G or C
+	// and Ambiguous is the fraction of all positions holding
+	// an ambiguous letter, including gap and ground state
+	// positions.
+	GC        float64 `json:"gc"`
+	Ambiguous float64 `json:"ambiguous"`
+}
+
+// Summarize returns the summary statistics for contigs. If genomeSize is greater
+// than zero, NG50 and LG50 are calculated against it. Positions covered by more
+// than one member are counted once, using the letter resolved by the Contig's Policy.
+// Summarize walks the step vector of each Contig and does not construct the
+// Contig's sequence.
+func Summarize(contigs []*Contig, genomeSize int) Stats {
+	st := Stats{Contigs: len(contigs), GenomeSize: genomeSize}
+	var (
+		lengths []int
+		gc, at  int
+	)
+	for _, c := range contigs {
+		n := c.Len()
+		lengths = append(lengths, n)
+		st.Length += n
+		st.Members += len(c.members)
+		if c.vector == nil {
+			continue
+		}
+		c.vector.Do(func(start, end int, e step.Equaler) {
+			switch e := e.(type) {
+			case ambig:
+				st.Ambiguous += float64(end - start)
+			case gapStep:
+				st.Ambiguous += float64(end - start)
+			case seqStep:
+				for i := start; i < end; i++ {
+					switch c.policy.resolve(i, e).L {
+					case 'G', 'C', 'g', 'c', 'S', 's':
+						gc++
+					case 'A', 'T', 'U', 'a', 't', 'u', 'W', 'w':
+						at++
+					default:
+						st.Ambiguous++
+					}
+				}
+			}
+		})
+		var (
+			ground  []int
+			members bool
+		)
+		c.walkTiles(func(start, end int, m *placement) {
+			_, gap := c.GapAt(start)
+			switch {
+			case m != nil:
+				// Runs of ground state are counted
+				// only once they are closed by a member.
+				if members {
+					for _, l := range ground {
+						st.Gaps++
+						st.GapLength += l
+					}
+				}
+				ground = ground[:0]
+				members = true
+			case gap:
+				st.Gaps++
+				st.GapLength += end - start
+			default:
+				ground = append(ground, end-start)
+			}
+		})
+	}
+	if len(lengths) == 0 {
+		return st
+	}
+	sort.Sort(sort.Reverse(sort.IntSlice(lengths)))
+	st.Max, st.Min = lengths[0], lengths[len(lengths)-1]
+	st.N50, st.L50 = nx50(lengths, st.Length)
+	if genomeSize > 0 {
+		st.NG50, st.LG50 = nx50(lengths, genomeSize)
+	}
+	if gc+at != 0 {
+		st.GC = float64(gc) / float64(gc+at)
+	}
+	if st.Length != 0 {
+		st.Ambiguous /= float64(st.Length)
+	}
+	return st
+}
+
+// nx50 returns the N50 and L50 of the lengths, which must be sorted in decreasing
+// order, against the given total.
+func nx50(lengths []int, total int) (n, l int) {
+	var sum int
+	for i, v := range lengths {
+		sum += v
+		if 2*sum >= total {
+			return v, i + 1
+		}
+	}
+	return 0, 0
+}
+
+// WriteTSV writes the statistics to w as a tab-separated header line and a line
+// of values.
+func (s Stats) WriteTSV(w io.Writer) error {
+	_, err := fmt.Fprintln(w, "contigs\tmembers\tlength\tmin\tmax\tn50\tl50\tgenome_size\tng50\tlg50\tgaps\tgap_length\tgc\tambiguous")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%d\t%.4f\t%.4f\n",
+		s.Contigs, s.Members, s.Length, s.Min, s.Max, s.N50, s.L50,
+		s.GenomeSize, s.NG50, s.LG50, s.Gaps, s.GapLength, s.GC, s.Ambiguous)
+	return err
+}
+
+// WriteJSON writes the statistics to w as a JSON object.
+func (s Stats) WriteJSON(w io.Writer) error {
+	return json.NewEncoder(w).Encode(s)
+}
@@ -0,0 +1,148 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"errors"
+
+	"github.com/biogo/biogo/feat"
+	"github.com/biogo/biogo/seq/sequtils"
+	"github.com/biogo/store/step"
+)
+
+// Subcontig returns a new Contig holding the region [start, end) of the Contig in
+// its current orientation. The returned Contig spans [0, end-start) and has the ID,
+// alphabet, ground state, policy and strictness of the receiver. Members overlapping
+// the region are cloned and truncated at the region's edges and retain their
+// insertion order and orientation relative to the Contig; gaps and masks are
+// clipped to the region. Features placed in Contig coordinates are retained if
+// they lie within the region and features anchored to a member are retained if
+// they lie within the retained part of the member.
+//
+// For a circular Contig, the region may extend past the Contig's end to wrap its
+// origin. If the region covers the entire circular Contig, the returned Contig is
+// circular, otherwise it is linear. Subcontig returns an error if the region would
+// divide a member into two parts.
+func (c *Contig) Subcontig(start, end int) (*Contig, error) {
+	n := end - start
+	if c.vector == nil || n < 0 {
+		return nil, errors.New("contig: invalid interval")
+	}
+	if c.IsCircular() {
+		if start < c.Start() || start >= c.End() || n > c.Len() {
+			return nil, errors.New("contig: interval out of range")
+		}
+		if n == c.Len() {
+			sub := c.Clone().(*Contig)
+			err := sub.Rotate(start)
+			if err != nil {
+				return nil, err
+			}
+			return sub, sub.SetOffset(0)
+		}
+	} else if start < c.Start() || end > c.End() {
+		return nil, errors.New("contig: interval out of range")
+	}
+
+	v, err := step.New(0, n, c.vector.Zero)
+	if err != nil {
+		return nil, err
+	}
+	v.Relaxed = c.vector.Relaxed
+	sub := &Contig{
+		Annotation: c.CloneAnnotation(),
+		policy:     c.policy,
+		strict:     c.strict,
+		groundQ:    c.groundQ,
+		encode:     c.encode,
+		masking:    c.masking,
+		index:      make(map[string]*placement),
+		vector:     v,
+	}
+	sub.Conform = feat.Linear
+
+	// Intervals of a circular Contig are considered at each
+	// position they may take relative to the region.
+	shifts := []int{0}
+	if c.IsCircular() {
+		shifts = []int{-c.Len(), 0, c.Len()}
+	}
+	clip := func(s, e, sh int) (int, int, bool) {
+		s, e = max(s+sh, start), min(e+sh, end)
+		return s, e, s < e
+	}
+
+	for _, g := range c.Gaps() {
+		for _, sh := range shifts {
+			if s, e, ok := clip(g.Start, g.End, sh); ok {
+				g.Start, g.End = s-start, e-start
+				sub.insertGap(g)
+			}
+		}
+	}
+
+	for _, r := range c.Masked() {
+		for _, sh := range shifts {
+			if s, e, ok := clip(r.Start, r.End, sh); ok {
+				sub.addMask(s-start, e-start)
+			}
+		}
+	}
+
+	clones := make(map[*placement]*placement)
+	segs := make(map[*placement]Segment)
+	for _, p := range c.members {
+		ms, me := c.span(p)
+		for _, sh := range shifts {
+			s, e, ok := clip(ms, me, sh)
+			if !ok {
+				continue
+			}
+			if _, ok := clones[p]; ok {
+				return nil, errors.New("contig: interval divides member")
+			}
+			seg := c.segment(p, s-sh, e-sh)
+			m := p.s.Clone()
+			err = sequtils.Truncate(m, p.s, p.s.Start()+seg.MemberStart, p.s.Start()+seg.MemberEnd)
+			if err != nil {
+				return nil, err
+			}
+			pc := &placement{s: m, start: s - start, frame: c.frameOf(p)}
+			clones[p], segs[p] = pc, seg
+			sub.insert(pc)
+		}
+	}
+
+	for _, a := range c.features {
+		if a.anchor == nil {
+			pl := c.place(a)
+			for _, sh := range shifts {
+				if pl.Start+sh >= start && pl.End+sh <= end {
+					sub.features = append(sub.features, &annot{
+						f:      a.f,
+						start:  pl.Start + sh - start,
+						end:    pl.End + sh - start,
+						orient: pl.Orientation,
+					})
+					break
+				}
+			}
+			continue
+		}
+		pc, ok := clones[a.anchor]
+		if !ok {
+			continue
+		}
+		seg := segs[a.anchor]
+		if a.start >= seg.MemberStart && a.end <= seg.MemberEnd {
+			ac := *a
+			ac.anchor = pc
+			ac.start -= seg.MemberStart
+			ac.end -= seg.MemberStart
+			sub.features = append(sub.features, &ac)
+		}
+	}
+	return sub, nil
+}
@@ -0,0 +1,258 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+package contig
+
+import (
+	"bufio"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/seq"
+)
+
+// twoBitSignature is the signature of a UCSC .2bit file.
+const twoBitSignature = 0x1a412743
+
+// WriteTwoBit writes the letters of the Contigs in their current orientations
+// to w in UCSC .2bit format. Overlapping positions are resolved according to each
+// Contig's Policy and masks are rendered according to each Contig's Masking. Letters
+// other than A, C, G and T are written as N blocks and lower case letters as mask
+// blocks. Each Contig is packed once to determine the file layout and once more
+// as it is written, so only one packed Contig is held at a time.
+func WriteTwoBit(w io.Writer, cons ...*Contig) error {
+	var (
+		order   binary.ByteOrder = binary.LittleEndian
+		version uint32
+		sizes   = make([]int64, len(cons))
+		index   int64
+		data    int64
+	)
+	for i, c := range cons {
+		if len(c.ID) > math.MaxUint8 {
+			return fmt.Errorf("contig: 2bit: name too long for %q", c.ID)
+		}
+		t := c.pack(true)
+		if int64(t.n) > math.MaxUint32 {
+			return fmt.Errorf("contig: 2bit: sequence too long for %q", c.ID)
+		}
+		sizes[i] = int64(4*(4+2*len(t.nBlocks)+2*len(t.maskBlocks))) + int64(t.n+3)/4
+		index += int64(1 + len(c.ID))
+		data += sizes[i]
+	}
+	offWidth := int64(4)
+	if 16+index+4*int64(len(cons))+data > math.MaxUint32 {
+		// Version 1 files use 64 bit offsets.
+		version, offWidth = 1, 8
+	}
+
+	bw := bufio.NewWriter(w)
+	put := func(v uint32) {
+		var b [4]byte
+		order.PutUint32(b[:], v)
+		bw.Write(b[:])
+	}
+	put(twoBitSignature)
+	put(version)
+	put(uint32(len(cons)))
+	put(0)
+	off := 16 + index + offWidth*int64(len(cons))
+	for i, c := range cons {
+		bw.WriteByte(byte(len(c.ID)))
+		bw.WriteString(c.ID)
+		if version == 1 {
+			var b [8]byte
+			order.PutUint64(b[:], uint64(off))
+			bw.Write(b[:])
+		} else {
+			put(uint32(off))
+		}
+		off += sizes[i]
+	}
+	for _, c := range cons {
+		t := c.pack(false)
+		put(uint32(t.n))
+		for _, blocks := range [][]Interval{t.nBlocks, t.maskBlocks} {
+			put(uint32(len(blocks)))
+			for _, b := range blocks {
+				put(uint32(b.Start))
+			}
+			for _, b := range blocks {
+				put(uint32(b.End - b.Start))
+			}
+		}
+		put(0)
+		_, err := bw.Write(t.bits)
+		if err != nil {
+			return err
+		}
+	}
+	return bw.Flush()
+}
+
+// twoBitReader reads the fields of a .2bit file, tracking the read position.
+type twoBitReader struct {
+	r     *bufio.Reader
+	order binary.ByteOrder
+	pos   int64
+	err   error
+}
+
+func (r *twoBitReader) read(b []byte) {
+	if r.err != nil {
+		return
+	}
+	var n int
+	n, r.err = io.ReadFull(r.r, b)
+	r.pos += int64(n)
+	if r.err == io.EOF {
+		r.err = io.ErrUnexpectedEOF
+	}
+}
+
+func (r *twoBitReader) uint32() uint32 {
+	var b [4]byte
+	r.read(b[:])
+	if r.err != nil {
+		return 0
+	}
+	return r.order.Uint32(b[:])
+}
+
+// bytes reads n bytes. The returned slice grows only as data is read, so a
+// corrupt length does not cause a large allocation.
+func (r *twoBitReader) bytes(n int) []byte {
+	const chunk = 1 << 16
+	var b []byte
+	for len(b) < n && r.err == nil {
+		l := min(n-len(b), chunk)
+		b = append(b, make([]byte, l)...)
+		r.read(b[len(b)-l:])
+	}
+	return b
+}
+
+// blocks reads a list of blocks. Like bytes, the returned slice grows only as
+// blocks are read.
+func (r *twoBitReader) blocks() []Interval {
+	n := int(r.uint32())
+	if r.err != nil {
+		return nil
+	}
+	var blocks []Interval
+	for i := 0; i < n && r.err == nil; i++ {
+		blocks = append(blocks, Interval{Start: int(r.uint32())})
+	}
+	for i := range blocks {
+		blocks[i].End = blocks[i].Start + int(r.uint32())
+	}
+	return blocks
+}
+
+// ReadTwoBit reads the sequences of a UCSC .2bit file from r and returns them as
+// Contigs in the order of the file's index. Each returned Contig spans [0, n)
+// for a sequence of length n, has the DNA alphabet and holds the sequence as a
+// single Packed member with the same name. Both version 0 and version 1 files,
+// in either byte order, are read.
+func ReadTwoBit(r io.Reader) ([]*Contig, error) {
+	tr := &twoBitReader{r: bufio.NewReader(r), order: binary.LittleEndian}
+	var b [4]byte
+	tr.read(b[:])
+	if tr.err != nil {
+		return nil, tr.err
+	}
+	switch {
+	case binary.LittleEndian.Uint32(b[:]) == twoBitSignature:
+	case binary.BigEndian.Uint32(b[:]) == twoBitSignature:
+		tr.order = binary.BigEndian
+	default:
+		return nil, errors.New("contig: 2bit: invalid signature")
+	}
+	version := tr.uint32()
+	if version > 1 {
+		return nil, fmt.Errorf("contig: 2bit: unsupported version %d", version)
+	}
+	n := int(tr.uint32())
+	tr.uint32()
+
+	type entry struct {
+		name string
+		off  int64
+		idx  int
+	}
+	// The index is not preallocated since
+	// n has not been checked against the input.
+	var index []entry
+	for i := 0; i < n && tr.err == nil; i++ {
+		var l [1]byte
+		tr.read(l[:])
+		name := make([]byte, l[0])
+		tr.read(name)
+		e := entry{name: string(name), idx: i}
+		if version == 1 {
+			var o [8]byte
+			tr.read(o[:])
+			e.off = int64(tr.order.Uint64(o[:]))
+		} else {
+			e.off = int64(tr.uint32())
+		}
+		index = append(index, e)
+	}
+	if tr.err != nil {
+		return nil, tr.err
+	}
+	sort.Slice(index, func(i, j int) bool { return index[i].off < index[j].off })
+
+	cons := make([]*Contig, len(index))
+	for _, e := range index {
+		if e.off < tr.pos {
+			return nil, fmt.Errorf("contig: 2bit: invalid offset for %q", e.name)
+		}
+		d, err := tr.r.Discard(int(e.off - tr.pos))
+		tr.pos += int64(d)
+		if err != nil {
+			return nil, err
+		}
+		p := &Packed{Annotation: seq.Annotation{ID: e.name, Alpha: alphabet.DNA, Strand: seq.Plus}}
+		p.n = int(tr.uint32())
+		p.nBlocks = tr.blocks()
+		p.maskBlocks = tr.blocks()
+		tr.uint32()
+		if tr.err != nil {
+			return nil, fmt.Errorf("contig: 2bit: %q: %v", e.name, tr.err)
+		}
+		if !validBlocks(p.nBlocks, p.n) || !validBlocks(p.maskBlocks, p.n) {
+			return nil, fmt.Errorf("contig: 2bit: %q: block out of range", e.name)
+		}
+		p.bits = tr.bytes((p.n + 3) / 4)
+		if tr.err != nil {
+			return nil, fmt.Errorf("contig: 2bit: %q: %v", e.name, tr.err)
+		}
+		c, err := New(e.name, p.n, alphabet.DNA)
+		if err != nil {
+			return nil, err
+		}
+		err = c.Insert(p)
+		if err != nil {
+			return nil, err
+		}
+		cons[e.idx] = c
+	}
+	return cons, nil
+}
+
+// validBlocks returns whether the blocks lie within a sequence of length n.
+func validBlocks(blocks []Interval, n int) bool {
+	for _, b := range blocks {
+		if b.Start < 0 || b.End < b.Start || b.End > n {
+			return false
+		}
+	}
+	return true
+}
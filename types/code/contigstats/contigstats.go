@@ -0,0 +1,108 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// contigstats is an example client of biogo.examples/contig. It reads scaffold
+// layouts from AGP or GFA files and prints assembly statistics for the complete
+// set of scaffolds as TSV or JSON.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"github.com/biogo/biogo/alphabet"
+	"github.com/biogo/biogo/seq"
+
+	"github.com/biogo/examples/contig"
+)
+
+var (
+	comps  = flag.String("components", "", "FASTA file of AGP component sequences")
+	genome = flag.Int("genome", 0, "expected genome size for NG50 calculation")
+	asJSON = flag.Bool("json", false, "write statistics as JSON")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [options] <layout.agp|layout.gfa>...\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() == 0 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	var cs map[string]seq.Sequence
+	if *comps != "" {
+		f, err := os.Open(*comps)
+		if err != nil {
+			fatal(err)
+		}
+		cs, err = contig.ReadComponents(f, alphabet.DNA)
+		f.Close()
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	var contigs []*contig.Contig
+	for _, path := range flag.Args() {
+		c, err := read(path, cs)
+		if err != nil {
+			fatal(err)
+		}
+		contigs = append(contigs, c...)
+	}
+
+	st := contig.Summarize(contigs, *genome)
+	var err error
+	if *asJSON {
+		err = st.WriteJSON(os.Stdout)
+	} else {
+		err = st.WriteTSV(os.Stdout)
+	}
+	if err != nil {
+		fatal(err)
+	}
+}
+
+// read returns the scaffolds described by the AGP or GFA file at path.
+func read(path string, comps map[string]seq.Sequence) ([]*contig.Contig, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch filepath.Ext(path) {
+	case ".agp":
+		if comps == nil {
+			return nil, fmt.Errorf("%s: AGP input requires -components", path)
+		}
+		var contigs []*contig.Contig
+		r := contig.NewAGPReader(f, comps, alphabet.DNA)
+		for {
+			c, err := r.Read()
+			if err != nil {
+				if err != io.EOF {
+					return nil, err
+				}
+				break
+			}
+			contigs = append(contigs, c)
+		}
+		return contigs, nil
+	case ".gfa":
+		return contig.ReadGFA(f, alphabet.DNA)
+	}
+	return nil, fmt.Errorf("%s: unknown layout format", path)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}
@@ -0,0 +1,128 @@
+// Copyright ©2026 The bíogo Authors. All rights reserved.
+// Use of this source code is governed by a BSD-style
+// license that can be found in the LICENSE file.
+
+// scaffold is an example client of biogo.examples/contig. It places contigs on
+// a reference using their alignments in PAF or SAM format, and writes the
+// resulting scaffold layouts as AGP and optionally their sequences as FASTA.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/biogo/biogo/alphabet"
+
+	"github.com/biogo/examples/contig"
+)
+
+var (
+	mapq     = flag.Int("mapq", 0, "minimum mapping quality of used alignments")
+	coverage = flag.Float64("coverage", 0, "minimum aligned fraction of a placed contig")
+	minGap   = flag.Int("mingap", 1, "minimum gap sized from the reference")
+	unknown  = flag.Int("unknown", contig.DefaultUnknownGap, "length of gaps of unknown size")
+	fasta    = flag.String("fasta", "", "write scaffold sequences to the named FASTA file")
+	unplaced = flag.Bool("unplaced", false, "write unplaced contigs as single component objects")
+)
+
+func main() {
+	flag.Usage = func() {
+		fmt.Fprintf(os.Stderr, "usage: %s [options] <contigs.fa> <alignments.paf|alignments.sam>\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
+	flag.Parse()
+	if flag.NArg() != 2 {
+		flag.Usage()
+		os.Exit(2)
+	}
+
+	f, err := os.Open(flag.Arg(0))
+	if err != nil {
+		fatal(err)
+	}
+	seqs, err := contig.ReadComponents(f, alphabet.DNA)
+	f.Close()
+	if err != nil {
+		fatal(err)
+	}
+	alns, err := read(flag.Arg(1))
+	if err != nil {
+		fatal(err)
+	}
+
+	sc := contig.Scaffolder{
+		MinMapQ:     *mapq,
+		MinCoverage: *coverage,
+		MinGap:      *minGap,
+		UnknownGap:  *unknown,
+	}
+	scaffolds, rest, err := sc.Scaffold(alns, seqs)
+	if err != nil {
+		fatal(err)
+	}
+	if *unplaced {
+		for _, id := range rest {
+			s := seqs[id]
+			c, err := contig.New(id, s.Len(), alphabet.DNA)
+			if err != nil {
+				fatal(err)
+			}
+			err = c.Insert(s)
+			if err != nil {
+				fatal(err)
+			}
+			scaffolds = append(scaffolds, c)
+		}
+	} else if len(rest) != 0 {
+		fmt.Fprintf(os.Stderr, "%d contigs not placed\n", len(rest))
+	}
+
+	agp := contig.NewAGPWriter(os.Stdout)
+	for _, c := range scaffolds {
+		err = agp.Write(c)
+		if err != nil {
+			fatal(err)
+		}
+	}
+
+	if *fasta != "" {
+		f, err := os.Create(*fasta)
+		if err != nil {
+			fatal(err)
+		}
+		w := contig.NewFASTAWriter(f, 60)
+		for _, c := range scaffolds {
+			err = w.Write(c)
+			if err != nil {
+				fatal(err)
+			}
+		}
+		err = f.Close()
+		if err != nil {
+			fatal(err)
+		}
+	}
+}
+
+// read returns the alignments held in the PAF or SAM file at path.
+func read(path string) ([]contig.Alignment, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	switch filepath.Ext(path) {
+	case ".paf":
+		return contig.ReadPAF(f)
+	case ".sam":
+		return contig.ReadSAM(f)
+	}
+	return nil, fmt.Errorf("%s: unknown alignment format", path)
+}
+
+func fatal(err error) {
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(1)
+}